@@ -8,10 +8,14 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/jonesrussell/go-fundamentals-best-practices/internal/api"
+	"github.com/jonesrussell/go-fundamentals-best-practices/internal/content"
 	"github.com/jonesrussell/go-fundamentals-best-practices/internal/executor"
 	"github.com/jonesrussell/go-fundamentals-best-practices/internal/parser"
 	"github.com/jonesrussell/go-fundamentals-best-practices/internal/storage"
@@ -26,11 +30,23 @@ const (
 	shutdownTimeout   = 30 * time.Second
 )
 
+// sectionIDMigrationFile records, in the tutorials directory, the stable ID of each section
+// that progress used to store by position.
+const sectionIDMigrationFile = "section-id-migration.json"
+
+// defaultContentPollInterval is how often tutorial files are checked for changes.
+const defaultContentPollInterval = 2 * time.Second
+
 // config holds application configuration.
 type config struct {
-	port         string
-	tutorialsDir string
-	dataDir      string
+	port           string
+	tutorialsDir   string
+	cheatsheetsDir string
+	dataDir        string
+	rateLimits     api.RateLimitConfig
+	executorOpts   []executor.ExecutorOption
+	pollInterval   time.Duration // Zero disables watching tutorial content
+	adminToken     string
 }
 
 func main() {
@@ -51,12 +67,16 @@ type dependencies struct {
 	parser   *parser.TutorialParser
 	executor *executor.CodeExecutor
 	storage  *storage.ProgressStorage
+	shares   *storage.ShareStorage
 	handlers *api.Handlers
 }
 
 // run contains the main application logic, separated for better testability.
 func run(logger *slog.Logger) error {
-	cfg := loadConfig()
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
 
 	deps, err := initializeDependencies(cfg, logger)
 	if err != nil {
@@ -67,6 +87,13 @@ func run(logger *slog.Logger) error {
 			logger.Error("cleanup code executor failed", "error", cleanupErr)
 		}
 	}()
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if closeErr := deps.handlers.Close(ctx); closeErr != nil {
+			logger.Error("close handlers failed", "error", closeErr)
+		}
+	}()
 
 	return runServer(cfg, deps.handlers, logger)
 }
@@ -81,7 +108,7 @@ func initializeDependencies(cfg config, logger *slog.Logger) (*dependencies, err
 
 	tutorialParser := parser.NewTutorialParser(cfg.tutorialsDir)
 
-	codeExecutor, err := executor.NewCodeExecutor()
+	codeExecutor, err := executor.NewCodeExecutor(cfg.executorOpts...)
 	if err != nil {
 		logger.Error("failed to create code executor", "error", err)
 		return nil, fmt.Errorf("create code executor: %w", err)
@@ -96,7 +123,25 @@ func initializeDependencies(cfg config, logger *slog.Logger) (*dependencies, err
 		return nil, fmt.Errorf("create progress storage: %w", err)
 	}
 
-	handlers, err := api.NewHandlers(tutorialParser, codeExecutor, progressStorage)
+	if migrateErr := migrateSectionIDs(cfg, progressStorage, logger); migrateErr != nil {
+		logger.Error("failed to migrate section IDs", "error", migrateErr)
+		if cleanupErr := codeExecutor.Cleanup(); cleanupErr != nil {
+			logger.Error("cleanup failed during section ID migration error", "error", cleanupErr)
+		}
+		return nil, fmt.Errorf("migrate section IDs: %w", migrateErr)
+	}
+
+	shareStorage, err := storage.NewShareStorage(cfg.dataDir)
+	if err != nil {
+		logger.Error("failed to create share storage", "error", err, "data_dir", cfg.dataDir)
+		if cleanupErr := codeExecutor.Cleanup(); cleanupErr != nil {
+			logger.Error("cleanup failed during share storage error", "error", cleanupErr)
+		}
+		return nil, fmt.Errorf("create share storage: %w", err)
+	}
+
+	handlers, err := api.NewHandlers(tutorialParser, codeExecutor, progressStorage, shareStorage,
+		content.WithCheatsheets(cfg.cheatsheetsDir))
 	if err != nil {
 		logger.Error("failed to create handlers", "error", err)
 		if cleanupErr := codeExecutor.Cleanup(); cleanupErr != nil {
@@ -104,20 +149,45 @@ func initializeDependencies(cfg config, logger *slog.Logger) (*dependencies, err
 		}
 		return nil, fmt.Errorf("create handlers: %w", err)
 	}
+	handlers.SetAdminToken(cfg.adminToken)
+	handlers.PruneShares(api.SharePruneInterval)
+	if cfg.pollInterval > 0 {
+		handlers.WatchContent(cfg.pollInterval)
+	}
 
 	return &dependencies{
 		parser:   tutorialParser,
 		executor: codeExecutor,
 		storage:  progressStorage,
+		shares:   shareStorage,
 		handlers: handlers,
 	}, nil
 }
 
+// migrateSectionIDs rewrites stored progress from positional section IDs to stable ones,
+// using the mapping recorded in the tutorials directory.
+func migrateSectionIDs(cfg config, progressStorage *storage.ProgressStorage, logger *slog.Logger) error {
+	migration, err := storage.LoadSectionIDMigration(filepath.Join(cfg.tutorialsDir, sectionIDMigrationFile))
+	if err != nil {
+		return err
+	}
+
+	migrated, err := progressStorage.MigrateSectionIDs(migration)
+	if err != nil {
+		return err
+	}
+	if migrated > 0 {
+		logger.Info("migrated progress to stable section IDs", "users", migrated)
+	}
+	return nil
+}
+
 // runServer starts the HTTP server and handles graceful shutdown.
 func runServer(cfg config, handlers *api.Handlers, logger *slog.Logger) error {
 	// Setup routes and middleware
 	mux := handlers.SetupRoutes()
-	handler := api.CORSMiddleware(mux)
+	limiter := api.NewRateLimiter(cfg.rateLimits, logger)
+	handler := api.CORSMiddleware(limiter.Middleware(mux))
 
 	// Create HTTP server with secure defaults
 	server := &http.Server{
@@ -135,7 +205,14 @@ func runServer(cfg config, handlers *api.Handlers, logger *slog.Logger) error {
 		logger.Info("server starting",
 			"port", cfg.port,
 			"tutorials_dir", cfg.tutorialsDir,
+			"cheatsheets_dir", cfg.cheatsheetsDir,
 			"data_dir", cfg.dataDir,
+			"rate_limit_execute", cfg.rateLimits.Execute.String(),
+			"rate_limit_check", cfg.rateLimits.Check.String(),
+			"rate_limit_format", cfg.rateLimits.Format.String(),
+			"rate_limit_share", cfg.rateLimits.Share.String(),
+			"content_poll_interval", cfg.pollInterval.String(),
+			"admin_enabled", cfg.adminToken != "",
 		)
 
 		serverErrors <- server.ListenAndServe()
@@ -172,12 +249,122 @@ func runServer(cfg config, handlers *api.Handlers, logger *slog.Logger) error {
 }
 
 // loadConfig loads configuration from environment variables with defaults.
-func loadConfig() config {
+func loadConfig() (config, error) {
+	rateLimits, err := loadRateLimitConfig()
+	if err != nil {
+		return config{}, err
+	}
+
+	toolchains, err := loadToolchainOptions()
+	if err != nil {
+		return config{}, err
+	}
+
+	limits, err := loadLimitOptions()
+	if err != nil {
+		return config{}, err
+	}
+
+	pollInterval := defaultContentPollInterval
+	if value := os.Getenv("CONTENT_POLL_INTERVAL"); value != "" {
+		pollInterval, err = time.ParseDuration(value)
+		if err != nil || pollInterval < 0 {
+			return config{}, fmt.Errorf("CONTENT_POLL_INTERVAL: invalid duration %q", value)
+		}
+	}
+
 	return config{
-		port:         getEnv("PORT", "8080"),
-		tutorialsDir: getEnv("TUTORIALS_DIR", "tutorials"),
-		dataDir:      getEnv("DATA_DIR", "data"),
+		port:           getEnv("PORT", "8080"),
+		tutorialsDir:   getEnv("TUTORIALS_DIR", "tutorials"),
+		cheatsheetsDir: getEnv("CHEATSHEETS_DIR", "cheatsheets"),
+		dataDir:        getEnv("DATA_DIR", "data"),
+		rateLimits:     rateLimits,
+		executorOpts:   append(toolchains, limits...),
+		pollInterval:   pollInterval,
+		adminToken:     os.Getenv("ADMIN_TOKEN"),
+	}, nil
+}
+
+// loadToolchainOptions loads extra toolchains such as GO_TOOLCHAINS=1.23=golang:1.23-alpine,1.24=golang:1.24-alpine
+// and the default toolchain from GO_DEFAULT_TOOLCHAIN.
+func loadToolchainOptions() ([]executor.ExecutorOption, error) {
+	var opts []executor.ExecutorOption
+
+	if value := os.Getenv("GO_TOOLCHAINS"); value != "" {
+		for _, entry := range strings.Split(value, ",") {
+			name, image, found := strings.Cut(strings.TrimSpace(entry), "=")
+			if !found || name == "" || image == "" {
+				return nil, fmt.Errorf("GO_TOOLCHAINS: invalid entry %q, expected <name>=<image>", entry)
+			}
+			opts = append(opts, executor.WithToolchain(name, image))
+		}
 	}
+
+	if name := os.Getenv("GO_DEFAULT_TOOLCHAIN"); name != "" {
+		opts = append(opts, executor.WithDefaultToolchain(name))
+	}
+
+	return opts, nil
+}
+
+// loadLimitOptions loads the most resources a single run may request, such as MAX_TIMEOUT=1m,
+// MAX_MEMORY_MB=512, MAX_CPU_PERCENT=100 and MAX_OUTPUT_BYTES=100000. A limit that is not set
+// stays at the executor's default for that resource, so runs cannot ask for more.
+func loadLimitOptions() ([]executor.ExecutorOption, error) {
+	var limits executor.Limits
+
+	if value := os.Getenv("MAX_TIMEOUT"); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("MAX_TIMEOUT: invalid duration %q", value)
+		}
+		limits.Timeout = d
+	}
+
+	ints := map[string]*int{
+		"MAX_MEMORY_MB":    &limits.MemoryMB,
+		"MAX_CPU_PERCENT":  &limits.CPUPercent,
+		"MAX_OUTPUT_BYTES": &limits.MaxOutput,
+	}
+	for key, target := range ints {
+		value := os.Getenv(key)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("%s: invalid value %q, expected a positive integer", key, value)
+		}
+		*target = n
+	}
+
+	return []executor.ExecutorOption{executor.WithLimits(limits)}, nil
+}
+
+// loadRateLimitConfig loads rate limit budgets such as RATE_LIMIT_EXECUTE=20/1m.
+func loadRateLimitConfig() (api.RateLimitConfig, error) {
+	cfg := api.DefaultRateLimitConfig()
+	cfg.TrustProxy = getEnv("RATE_LIMIT_TRUST_PROXY", "false") == "true"
+
+	budgets := map[string]*api.RateBudget{
+		"RATE_LIMIT_EXECUTE": &cfg.Execute,
+		"RATE_LIMIT_CHECK":   &cfg.Check,
+		"RATE_LIMIT_FORMAT":  &cfg.Format,
+		"RATE_LIMIT_SHARE":   &cfg.Share,
+	}
+	for key, budget := range budgets {
+		value := os.Getenv(key)
+		if value == "" {
+			continue
+		}
+		parsed, err := api.ParseRateBudget(value)
+		if err != nil {
+			return api.RateLimitConfig{}, fmt.Errorf("%s: %w", key, err)
+		}
+		*budget = parsed
+	}
+
+	return cfg, nil
 }
 
 // getEnv retrieves an environment variable or returns a default value.
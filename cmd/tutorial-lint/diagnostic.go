@@ -0,0 +1,87 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// Diagnostic severities. Errors make the command exit non-zero.
+const (
+	severityError   = "error"
+	severityWarning = "warning"
+)
+
+// Diagnostic rules, which name the check that found a problem.
+const (
+	ruleParse        = "parse"
+	ruleFrontMatter  = "front-matter"
+	ruleInstructor   = "instructor-notes"
+	ruleNumbering    = "numbering"
+	ruleTOC          = "table-of-contents"
+	rulePrerequisite = "prerequisite"
+	ruleDuplicateID  = "duplicate-id"
+	ruleLink         = "link"
+	ruleFenceAttrs   = "fence-attribute"
+)
+
+// Diagnostic is a problem found in the tutorial content.
+type Diagnostic struct {
+	Severity string `json:"severity"`
+	Rule     string `json:"rule"`
+	File     string `json:"file"`
+	Line     int    `json:"line,omitempty"` // 1-based, 0 when the problem is with the file as a whole
+	Message  string `json:"message"`
+}
+
+// report is the JSON output of the command.
+type report struct {
+	Diagnostics []Diagnostic `json:"diagnostics"`
+	Errors      int          `json:"errors"`
+	Warnings    int          `json:"warnings"`
+}
+
+// newReport sorts the diagnostics by file and line and counts them by severity.
+func newReport(diags []Diagnostic) report {
+	slices.SortStableFunc(diags, func(a, b Diagnostic) int {
+		return cmp.Or(cmp.Compare(a.File, b.File), cmp.Compare(a.Line, b.Line))
+	})
+
+	r := report{Diagnostics: diags}
+	if r.Diagnostics == nil {
+		r.Diagnostics = []Diagnostic{}
+	}
+	for _, d := range diags {
+		if d.Severity == severityError {
+			r.Errors++
+		} else {
+			r.Warnings++
+		}
+	}
+	return r
+}
+
+// writeText writes one diagnostic per line in the file:line: severity: message form editors
+// understand, followed by a summary.
+func writeText(w io.Writer, r report) error {
+	for _, d := range r.Diagnostics {
+		location := d.File
+		if d.Line > 0 {
+			location = fmt.Sprintf("%s:%d", d.File, d.Line)
+		}
+		if _, err := fmt.Fprintf(w, "%s: %s: %s (%s)\n", location, d.Severity, d.Message, d.Rule); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "%d errors, %d warnings\n", r.Errors, r.Warnings)
+	return err
+}
+
+// writeJSON writes the report as indented JSON.
+func writeJSON(w io.Writer, r report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
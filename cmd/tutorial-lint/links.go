@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/jonesrussell/go-fundamentals-best-practices/internal/parser"
+)
+
+var (
+	// markdownLink matches inline links and images, capturing the target, e.g. [text](../a.md#b "title").
+	markdownLink = regexp.MustCompile(`\]\(\s*<?([^)\s>]+)>?(?:\s+"[^"]*")?\s*\)`)
+	// inlineCode matches code spans, whose contents are not links.
+	inlineCode = regexp.MustCompile("`[^`]*`")
+	// atxHeading matches a heading line, capturing its text without the closing hashes.
+	atxHeading = regexp.MustCompile(`^#{1,6}\s+(.*?)(?:\s+#+)?\s*$`)
+)
+
+// checkLinks reports relative links to files that do not exist and anchors that match no heading.
+func (l *linter) checkLinks(path, content string) {
+	body, offset := parser.SplitFrontMatter(content)
+
+	for i, line := range proseLines(body) {
+		if line == "" {
+			continue
+		}
+		for _, match := range markdownLink.FindAllStringSubmatch(inlineCode.ReplaceAllString(line, ""), -1) {
+			if problem := l.linkProblem(path, match[1]); problem != "" {
+				l.reportf(severityError, ruleLink, path, i+1+offset, "broken link %q: %s", match[1], problem)
+			}
+		}
+	}
+}
+
+// linkProblem describes what is wrong with a link target in the file at path, or returns ""
+// when it resolves. Absolute URLs and site paths are not checked.
+func (l *linter) linkProblem(path, target string) string {
+	if strings.Contains(target, "://") || strings.HasPrefix(target, "mailto:") || strings.HasPrefix(target, "/") {
+		return ""
+	}
+
+	file, anchor, _ := strings.Cut(target, "#")
+	if unescaped, err := url.PathUnescape(file); err == nil {
+		file = unescaped
+	}
+
+	targetPath := path
+	if file != "" {
+		targetPath = filepath.Join(filepath.Dir(path), filepath.FromSlash(file))
+		if _, err := os.Stat(targetPath); err != nil {
+			return fmt.Sprintf("%s does not exist", targetPath)
+		}
+	}
+
+	if anchor == "" || !strings.HasSuffix(targetPath, ".md") {
+		return ""
+	}
+	if !slices.Contains(l.headingAnchors(targetPath), anchor) {
+		return fmt.Sprintf("no heading in %s has the anchor #%s", targetPath, anchor)
+	}
+	return ""
+}
+
+// headingAnchors returns the anchors of the headings in a Markdown file.
+func (l *linter) headingAnchors(path string) []string {
+	if anchors, ok := l.anchors[path]; ok {
+		return anchors
+	}
+
+	var anchors []string
+	if content, err := os.ReadFile(path); err == nil {
+		body, _ := parser.SplitFrontMatter(string(content))
+		used := make(parser.HeadingAnchors)
+		for _, line := range proseLines(body) {
+			if match := atxHeading.FindStringSubmatch(strings.TrimSpace(line)); match != nil {
+				anchors = append(anchors, used.Next(match[1]))
+			}
+		}
+	}
+
+	l.anchors[path] = anchors
+	return anchors
+}
+
+// proseLines splits Markdown into lines, blanking the lines inside fenced code blocks so
+// line numbers still match.
+func proseLines(body string) []string {
+	lines := strings.Split(body, "\n")
+	fence := ""
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case fence != "":
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			lines[i] = ""
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			fence = trimmed[:3]
+			lines[i] = ""
+		}
+	}
+	return lines
+}
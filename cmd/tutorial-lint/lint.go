@@ -0,0 +1,300 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jonesrussell/go-fundamentals-best-practices/internal/parser"
+	"github.com/jonesrussell/go-fundamentals-best-practices/pkg/models"
+)
+
+// linter collects the diagnostics for a tutorials directory.
+type linter struct {
+	dir     string
+	parser  *parser.DirectoryParser
+	diags   []Diagnostic
+	anchors map[string][]string // Markdown file -> anchors of its headings
+}
+
+// tutorial is a tutorial directory being linted.
+type tutorial struct {
+	id         string
+	dir        string
+	config     *parser.TutorialConfig
+	configPath string
+	configText string
+}
+
+// lint checks every tutorial in dir and returns what it found.
+func lint(dir string) ([]Diagnostic, error) {
+	l := &linter{
+		dir:     dir,
+		parser:  parser.NewDirectoryParser(dir),
+		anchors: make(map[string][]string),
+	}
+
+	ids, err := l.parser.ListTutorialDirectories()
+	if err != nil {
+		return nil, err
+	}
+
+	var tutorials []tutorial
+	known := make(map[string]string) // Tutorial ID -> directory it is defined in
+	for _, id := range ids {
+		t := tutorial{id: id, dir: filepath.Join(dir, "tutorial-"+id)}
+		t.configPath = filepath.Join(t.dir, "tutorial.yaml")
+
+		t.config, err = l.parser.LoadTutorialConfig(id)
+		if err != nil {
+			l.reportf(severityError, ruleParse, t.configPath, 0, "%v", err)
+			continue
+		}
+		if data, readErr := os.ReadFile(t.configPath); readErr == nil {
+			t.configText = string(data)
+		}
+
+		if other, dup := known[t.config.ID]; dup {
+			l.reportf(severityError, ruleDuplicateID, t.configPath, findLine(t.configText, "id:"),
+				"tutorial ID %q is also used by %s", t.config.ID, other)
+			continue
+		}
+		known[t.config.ID] = t.dir
+		tutorials = append(tutorials, t)
+	}
+
+	for i := range tutorials {
+		l.lintTutorial(&tutorials[i], known)
+	}
+	return l.diags, nil
+}
+
+// reportf records a diagnostic. The message is kept to one line, as some errors it wraps,
+// such as YAML ones, span several.
+func (l *linter) reportf(severity, rule, file string, line int, format string, args ...any) {
+	l.diags = append(l.diags, Diagnostic{
+		Severity: severity,
+		Rule:     rule,
+		File:     file,
+		Line:     line,
+		Message:  strings.Join(strings.Fields(fmt.Sprintf(format, args...)), " "),
+	})
+}
+
+// lintTutorial checks a tutorial's sections, instructor notes and configuration.
+func (l *linter) lintTutorial(t *tutorial, known map[string]string) {
+	files, err := l.parser.ListSectionFiles(t.id)
+	if err != nil {
+		l.reportf(severityError, ruleParse, filepath.Join(t.dir, "sections"), 0, "%v", err)
+		return
+	}
+
+	l.checkNumbering(t, files)
+	l.checkInstructorNotes(t, files)
+	sections := l.lintSections(t, files)
+	l.checkTableOfContents(t, sections)
+	l.checkPrerequisites(t, sections, known)
+}
+
+// lintSections checks each section file and returns the parsed sections in order.
+func (l *linter) lintSections(t *tutorial, files []string) []*models.Section {
+	sections := make([]*models.Section, 0, len(files))
+	sectionFiles := make(map[string]string) // Section ID -> file that uses it
+
+	for i, filename := range files {
+		path := filepath.Join(t.dir, "sections", filename)
+		content, err := os.ReadFile(path)
+		if err != nil {
+			l.reportf(severityError, ruleParse, path, 0, "%v", err)
+			continue
+		}
+
+		if fmErr := parser.ValidateFrontMatter(string(content)); fmErr != nil {
+			l.reportf(severityError, ruleFrontMatter, path, 1, "%v", fmErr)
+		}
+
+		section, err := l.parser.ParseSectionFile(t.id, filename, i+1)
+		if err != nil {
+			l.reportf(severityError, ruleParse, path, 0, "%v", err)
+			continue
+		}
+		if other, dup := sectionFiles[section.ID]; dup {
+			l.reportf(severityError, ruleDuplicateID, path, 0, "section ID %q is also used by %s", section.ID, other)
+		}
+		sectionFiles[section.ID] = filename
+		sections = append(sections, section)
+
+		l.checkFences(path, string(content))
+		l.checkLinks(path, string(content))
+	}
+
+	return sections
+}
+
+// orderNumber matches the number a section filename starts with, e.g. "03" in "03-variables.md".
+var orderNumber = regexp.MustCompile(`^(\d+)`)
+
+// checkNumbering reports section files without an order number, numbers used twice and
+// numbers skipped between the first section and the last.
+func (l *linter) checkNumbering(t *tutorial, files []string) {
+	sectionsDir := filepath.Join(t.dir, "sections")
+	numbered := make(map[int]string)
+	highest := 0
+
+	for _, filename := range files {
+		path := filepath.Join(sectionsDir, filename)
+		match := orderNumber.FindString(filename)
+		if match == "" {
+			l.reportf(severityWarning, ruleNumbering, path, 0, "section file has no order number, so it sorts by name")
+			continue
+		}
+
+		n, _ := strconv.Atoi(match) // Only digits, so it parses
+		if other, dup := numbered[n]; dup {
+			l.reportf(severityError, ruleNumbering, path, 0, "section number %s is also used by %s", match, other)
+			continue
+		}
+		numbered[n] = filename
+		highest = max(highest, n)
+	}
+
+	for n := 1; n < highest; n++ {
+		if _, ok := numbered[n]; !ok {
+			l.reportf(severityWarning, ruleNumbering, sectionsDir, 0, "no section is numbered %02d", n)
+		}
+	}
+}
+
+// checkInstructorNotes reports sections without instructor notes and notes without a section.
+func (l *linter) checkInstructorNotes(t *tutorial, files []string) {
+	instructorDir := filepath.Join(t.dir, "instructor")
+	entries, err := os.ReadDir(instructorDir)
+	if os.IsNotExist(err) {
+		l.reportf(severityWarning, ruleInstructor, t.dir, 0, "tutorial has no instructor notes")
+		return
+	}
+	if err != nil {
+		l.reportf(severityError, ruleParse, instructorDir, 0, "%v", err)
+		return
+	}
+
+	var notes []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".md") {
+			notes = append(notes, entry.Name())
+		}
+	}
+
+	for _, filename := range files {
+		if !slices.Contains(notes, filename) {
+			l.reportf(severityWarning, ruleInstructor, filepath.Join(t.dir, "sections", filename), 0,
+				"no instructor notes, expected instructor/%s", filename)
+		}
+	}
+	for _, filename := range notes {
+		if !slices.Contains(files, filename) {
+			l.reportf(severityError, ruleInstructor, filepath.Join(instructorDir, filename), 0,
+				"instructor notes have no matching section, so they are never shown")
+		}
+	}
+}
+
+// tocEntry matches a numbered table of contents entry, e.g. "1. **Introduction** - Welcome".
+var tocEntry = regexp.MustCompile(`(?m)^\s*(\d+)\.\s+\*\*(.+?)\*\*`)
+
+// checkTableOfContents reports table of contents entries whose title differs from the section
+// in the same position, and sections the table of contents leaves out.
+func (l *linter) checkTableOfContents(t *tutorial, sections []*models.Section) {
+	entries := tocEntry.FindAllStringSubmatch(t.config.TableOfContents, -1)
+	if len(entries) == 0 {
+		return
+	}
+
+	for _, entry := range entries {
+		n, _ := strconv.Atoi(entry[1]) // Only digits, so it parses
+		title := strings.TrimSpace(entry[2])
+		line := findLine(t.configText, "**"+entry[2]+"**")
+
+		if n < 1 || n > len(sections) {
+			l.reportf(severityWarning, ruleTOC, t.configPath, line,
+				"table of contents entry %d %q has no matching section", n, title)
+			continue
+		}
+		if sectionTitle := sections[n-1].Title; !strings.EqualFold(title, sectionTitle) {
+			l.reportf(severityWarning, ruleTOC, t.configPath, line,
+				"table of contents entry %d is %q, but the section is titled %q", n, title, sectionTitle)
+		}
+	}
+
+	if len(entries) < len(sections) {
+		l.reportf(severityWarning, ruleTOC, t.configPath, findLine(t.configText, "tableOfContents:"),
+			"table of contents lists %d of %d sections", len(entries), len(sections))
+	}
+}
+
+// checkPrerequisites reports tutorial prerequisites that reference unknown tutorials, and section
+// prerequisites that reference unknown sections or tutorials.
+func (l *linter) checkPrerequisites(t *tutorial, sections []*models.Section, known map[string]string) {
+	for _, prerequisite := range t.config.Prerequisites {
+		if id, ok := parser.PrerequisiteTutorialID(prerequisite); ok && known[id] == "" {
+			l.reportf(severityError, rulePrerequisite, t.configPath, findLine(t.configText, prerequisite),
+				"prerequisite %q references unknown tutorial %q", prerequisite, id)
+		}
+	}
+
+	sectionIDs := make([]string, 0, len(sections))
+	for _, section := range sections {
+		sectionIDs = append(sectionIDs, section.ID)
+	}
+
+	for _, section := range sections {
+		path := filepath.Join(l.dir, filepath.FromSlash(section.Source))
+		for _, prerequisite := range section.Prerequisites {
+			id, isTutorial := parser.PrerequisiteTutorialID(prerequisite)
+			switch {
+			case isTutorial && known[id] == "":
+				l.reportf(severityError, rulePrerequisite, path, 0,
+					"prerequisite %q references unknown tutorial %q", prerequisite, id)
+			case !isTutorial && !slices.Contains(sectionIDs, prerequisite):
+				l.reportf(severityError, rulePrerequisite, path, 0,
+					"prerequisite %q is not a section of this tutorial", prerequisite)
+			}
+		}
+	}
+}
+
+// checkFences reports fences with attributes the parser does not understand, which are
+// usually typos, and code example IDs used twice in a section.
+func (l *linter) checkFences(path, content string) {
+	body, offset := parser.SplitFrontMatter(content)
+	exampleLines := make(map[string]int) // Explicit example ID -> line that sets it
+
+	for _, fence := range parser.Fences(body) {
+		line := fence.Line + offset
+		for _, attr := range fence.Unknown {
+			l.reportf(severityError, ruleFenceAttrs, path, line, "unknown attribute %q on %s fence", attr, fence.Language)
+		}
+
+		if fence.ID == "" {
+			continue
+		}
+		if other, dup := exampleLines[fence.ID]; dup {
+			l.reportf(severityError, ruleDuplicateID, path, line, "code example ID %q is also used on line %d", fence.ID, other)
+			continue
+		}
+		exampleLines[fence.ID] = line
+	}
+}
+
+// findLine returns the 1-based line of the first occurrence of text in content, or 0.
+func findLine(content, text string) int {
+	i := strings.Index(content, text)
+	if i < 0 {
+		return 0
+	}
+	return strings.Count(content[:i], "\n") + 1
+}
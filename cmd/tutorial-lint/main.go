@@ -0,0 +1,76 @@
+// Command tutorial-lint checks the tutorial content for consistency problems: missing or
+// orphaned instructor notes, gaps in section numbering, tables of contents that disagree with
+// the sections, unknown prerequisites, duplicate IDs, broken relative links and unknown fence
+// attributes. It exits non-zero when it finds an error.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// config holds command line configuration.
+type config struct {
+	tutorialsDir string
+	format       string
+}
+
+func main() {
+	cfg, err := parseFlags()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	diags, err := lint(cfg.tutorialsDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "tutorial-lint: %v\n", err)
+		os.Exit(1)
+	}
+
+	r := newReport(diags)
+	if err := write(os.Stdout, cfg.format, r); err != nil {
+		fmt.Fprintf(os.Stderr, "tutorial-lint: write report: %v\n", err)
+		os.Exit(1)
+	}
+	if r.Errors > 0 {
+		os.Exit(1)
+	}
+}
+
+// parseFlags reads the command line flags.
+func parseFlags() (config, error) {
+	var cfg config
+	flag.StringVar(&cfg.tutorialsDir, "tutorials", getEnv("TUTORIALS_DIR", "tutorials"), "tutorials directory")
+	flag.StringVar(&cfg.format, "format", formatText, "output format: text or json")
+	flag.Parse()
+
+	if cfg.format != formatText && cfg.format != formatJSON {
+		return cfg, fmt.Errorf("unknown -format %q, want %s or %s", cfg.format, formatText, formatJSON)
+	}
+	return cfg, nil
+}
+
+// Output formats.
+const (
+	formatText = "text"
+	formatJSON = "json"
+)
+
+// write writes the report in the given format.
+func write(w io.Writer, format string, r report) error {
+	if format == formatJSON {
+		return writeJSON(w, r)
+	}
+	return writeText(w, r)
+}
+
+// getEnv retrieves an environment variable or returns a default value.
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
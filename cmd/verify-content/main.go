@@ -0,0 +1,223 @@
+// Command verify-content runs every runnable code example in the tutorials and reports
+// the ones that fail to compile, exit with an error or print unexpected output. Examples
+// that demonstrate an error are reported when they do not fail the way they should.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/jonesrussell/go-fundamentals-best-practices/internal/executor"
+	"github.com/jonesrussell/go-fundamentals-best-practices/internal/parser"
+	"github.com/jonesrussell/go-fundamentals-best-practices/pkg/models"
+)
+
+// defaultParallelism is how many examples run at once unless -parallel is set.
+const defaultParallelism = 4
+
+// config holds command line configuration.
+type config struct {
+	tutorialsDir string
+	parallelism  int
+	junitPath    string
+	markdownPath string
+}
+
+// example is a runnable code example together with where it was found.
+type example struct {
+	tutorial string
+	source   string
+	code     models.CodeExample
+}
+
+// outcome is the result of running one example.
+type outcome struct {
+	example
+	result   *executor.ExecutionResult
+	err      error // The example could not be run at all
+	duration time.Duration
+}
+
+// broken reports whether the example failed to run, printed unexpected output, or exited with an
+// error other than the one it demonstrates. An example that should fail but succeeds is broken too.
+func (o *outcome) broken() bool {
+	if o.err != nil || (o.result.Check != nil && !o.result.Check.Passed) {
+		return true
+	}
+	if o.result.Failure != nil {
+		return !o.result.Failure.Passed
+	}
+	return o.result.ExitCode != 0
+}
+
+func main() {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: slog.LevelWarn,
+	}))
+	slog.SetDefault(logger)
+
+	cfg := parseFlags()
+
+	broken, err := run(cfg, logger)
+	if err != nil {
+		logger.Error("content verification failed", "error", err)
+		os.Exit(1)
+	}
+	if broken > 0 {
+		os.Exit(1)
+	}
+}
+
+// parseFlags reads the command line flags.
+func parseFlags() config {
+	var cfg config
+	flag.StringVar(&cfg.tutorialsDir, "tutorials", getEnv("TUTORIALS_DIR", "tutorials"), "tutorials directory")
+	flag.IntVar(&cfg.parallelism, "parallel", defaultParallelism, "number of examples to run at once")
+	flag.StringVar(&cfg.junitPath, "junit", "verify-content.xml", "JUnit XML report path, empty to skip")
+	flag.StringVar(&cfg.markdownPath, "markdown", "verify-content.md", "Markdown report path, empty to skip")
+	flag.Parse()
+
+	cfg.parallelism = max(cfg.parallelism, 1)
+	return cfg
+}
+
+// run verifies all examples, writes the reports and returns the number of broken examples.
+func run(cfg config, logger *slog.Logger) (int, error) {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	tutorials, err := parser.NewTutorialParser(cfg.tutorialsDir).LoadAllTutorials()
+	if err != nil {
+		return 0, fmt.Errorf("load tutorials: %w", err)
+	}
+
+	codeExecutor, err := executor.NewCodeExecutor(executor.WithLogger(logger))
+	if err != nil {
+		return 0, fmt.Errorf("create code executor: %w", err)
+	}
+	defer func() {
+		if cleanupErr := codeExecutor.Cleanup(); cleanupErr != nil {
+			logger.Error("cleanup code executor failed", "error", cleanupErr)
+		}
+	}()
+
+	outcomes := verify(ctx, codeExecutor, collectExamples(tutorials), cfg.parallelism)
+	if ctx.Err() != nil {
+		return 0, fmt.Errorf("verification interrupted: %w", ctx.Err())
+	}
+
+	if cfg.junitPath != "" {
+		if writeErr := writeReport(cfg.junitPath, outcomes, writeJUnit); writeErr != nil {
+			return 0, fmt.Errorf("write JUnit report: %w", writeErr)
+		}
+	}
+	if cfg.markdownPath != "" {
+		if writeErr := writeReport(cfg.markdownPath, outcomes, writeMarkdown); writeErr != nil {
+			return 0, fmt.Errorf("write Markdown report: %w", writeErr)
+		}
+	}
+
+	broken := 0
+	for i := range outcomes {
+		if outcomes[i].broken() {
+			broken++
+		}
+	}
+	fmt.Printf("%d examples verified, %d broken\n", len(outcomes), broken)
+
+	return broken, nil
+}
+
+// collectExamples returns the Go examples that can be run, in tutorial order.
+func collectExamples(tutorials []*models.Tutorial) []example {
+	var examples []example
+	for _, tutorial := range tutorials {
+		for _, section := range tutorial.Sections {
+			for _, code := range section.CodeExamples {
+				if code.Language != "go" || (!code.Runnable && !code.Snippet) {
+					continue
+				}
+				examples = append(examples, example{tutorial: tutorial.ID, source: section.Source, code: code})
+			}
+		}
+	}
+	return examples
+}
+
+// verify runs the examples, at most parallelism at a time, and returns their outcomes in order.
+func verify(ctx context.Context, codeExecutor *executor.CodeExecutor, examples []example, parallelism int) []outcome {
+	outcomes := make([]outcome, len(examples))
+	sem := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+
+	for i, ex := range examples {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			start := time.Now()
+			result, err := codeExecutor.Run(ctx, ex.code.Code, runOptions(ex.code))
+			outcomes[i] = outcome{example: ex, result: result, err: err, duration: time.Since(start)}
+		}()
+	}
+
+	wg.Wait()
+	return outcomes
+}
+
+// runOptions converts an example's settings into executor options, like the frontend does
+// when a reader runs the example.
+func runOptions(code models.CodeExample) executor.RunOptions {
+	opts := executor.RunOptions{
+		Snippet:   code.Snippet,
+		Toolchain: code.Toolchain,
+	}
+
+	for _, fixture := range code.Fixtures {
+		opts.Files = append(opts.Files, executor.InputFile{
+			Name:     fixture.Name,
+			Content:  fixture.Content,
+			Encoding: fixture.Encoding,
+		})
+	}
+
+	if code.Run != nil {
+		opts.Timeout, _ = time.ParseDuration(code.Run.Timeout) // Validated by the parser
+		opts.MemoryMB = code.Run.Memory
+		opts.CPUPercent = code.Run.CPU
+		opts.MaxOutput = code.Run.MaxOutput
+	}
+
+	if code.ExpectedOutput != "" {
+		opts.Expect = &executor.OutputExpectation{
+			Output:    code.ExpectedOutput,
+			Unordered: code.UnorderedOutput,
+		}
+	}
+
+	if failure := code.ExpectedFailure; failure != nil {
+		opts.ExpectFailure = &executor.FailureExpectation{
+			Kind:    failure.Kind,
+			Message: failure.Message,
+		}
+	}
+
+	return opts
+}
+
+// getEnv retrieves an environment variable or returns a default value.
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jonesrussell/go-fundamentals-best-practices/internal/executor"
+)
+
+// secondsFormat formats durations in JUnit reports.
+const secondsFormat = "%.3f"
+
+// writeReport creates a report file and writes the outcomes to it with write.
+func writeReport(path string, outcomes []outcome, write func(io.Writer, []outcome) error) (err error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	w := bufio.NewWriter(f)
+	if err := write(w, outcomes); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// name identifies the example by file and line.
+func (o *outcome) name() string {
+	return fmt.Sprintf("%s:%d", o.source, o.code.Line)
+}
+
+// problem summarises why a broken example failed and returns the output that shows it.
+func (o *outcome) problem() (summary, details string) {
+	if o.err != nil {
+		return "could not run", o.err.Error()
+	}
+
+	var b strings.Builder
+	switch {
+	case o.result.Failure != nil && !o.result.Failure.Passed:
+		summary = o.result.Failure.Reason
+		b.WriteString(strings.TrimSpace(o.result.Error))
+	case o.result.Failure == nil && o.result.ExitCode != 0:
+		summary = fmt.Sprintf("exited with code %d", o.result.ExitCode)
+		b.WriteString(strings.TrimSpace(o.result.Error))
+	}
+	if o.result.Check != nil && !o.result.Check.Passed {
+		if summary == "" {
+			summary = "unexpected output"
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n\n")
+		}
+		writeDiff(&b, o.result.Check.Diff)
+	}
+	return summary, b.String()
+}
+
+// writeDiff writes an output diff in unified diff style.
+func writeDiff(b *strings.Builder, diff []executor.DiffLine) {
+	for _, line := range diff {
+		prefix := " "
+		if line.Op != executor.DiffEqual {
+			prefix = line.Op
+		}
+		fmt.Fprintf(b, "%s %s\n", prefix, line.Text)
+	}
+}
+
+// JUnit XML report elements.
+type (
+	junitTestSuites struct {
+		XMLName  xml.Name         `xml:"testsuites"`
+		Name     string           `xml:"name,attr"`
+		Tests    int              `xml:"tests,attr"`
+		Failures int              `xml:"failures,attr"`
+		Errors   int              `xml:"errors,attr"`
+		Suites   []junitTestSuite `xml:"testsuite"`
+	}
+
+	junitTestSuite struct {
+		Name     string          `xml:"name,attr"`
+		Tests    int             `xml:"tests,attr"`
+		Failures int             `xml:"failures,attr"`
+		Errors   int             `xml:"errors,attr"`
+		Time     string          `xml:"time,attr"`
+		Cases    []junitTestCase `xml:"testcase"`
+	}
+
+	junitTestCase struct {
+		Name      string        `xml:"name,attr"`
+		Classname string        `xml:"classname,attr"`
+		Time      string        `xml:"time,attr"`
+		Failure   *junitProblem `xml:"failure,omitempty"`
+		Error     *junitProblem `xml:"error,omitempty"`
+	}
+
+	junitProblem struct {
+		Message string `xml:"message,attr"`
+		Text    string `xml:",chardata"`
+	}
+)
+
+// writeJUnit writes the outcomes as JUnit XML with one test suite per tutorial.
+func writeJUnit(w io.Writer, outcomes []outcome) error {
+	report := junitTestSuites{Name: "verify-content"}
+	seconds := map[string]float64{}
+
+	for i := range outcomes {
+		o := &outcomes[i]
+		suiteName := "tutorial-" + o.tutorial
+		if n := len(report.Suites); n == 0 || report.Suites[n-1].Name != suiteName {
+			report.Suites = append(report.Suites, junitTestSuite{Name: suiteName})
+		}
+		suite := &report.Suites[len(report.Suites)-1]
+
+		testCase := junitTestCase{
+			Name:      fmt.Sprintf("%s (%s)", o.name(), o.code.ID),
+			Classname: o.source,
+			Time:      fmt.Sprintf(secondsFormat, o.duration.Seconds()),
+		}
+		if o.broken() {
+			summary, details := o.problem()
+			problem := &junitProblem{Message: summary, Text: details}
+			if o.err != nil {
+				testCase.Error = problem
+				suite.Errors++
+				report.Errors++
+			} else {
+				testCase.Failure = problem
+				suite.Failures++
+				report.Failures++
+			}
+		}
+
+		suite.Cases = append(suite.Cases, testCase)
+		suite.Tests++
+		report.Tests++
+		seconds[suiteName] += o.duration.Seconds()
+	}
+
+	for i := range report.Suites {
+		report.Suites[i].Time = fmt.Sprintf(secondsFormat, seconds[report.Suites[i].Name])
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// writeMarkdown writes the broken examples as Markdown, grouped by the file they are in.
+func writeMarkdown(w io.Writer, outcomes []outcome) error {
+	var b strings.Builder
+	b.WriteString("# Content verification\n\n")
+
+	var broken []*outcome
+	for i := range outcomes {
+		if outcomes[i].broken() {
+			broken = append(broken, &outcomes[i])
+		}
+	}
+
+	fmt.Fprintf(&b, "%d examples verified, %d broken.\n", len(outcomes), len(broken))
+
+	source := ""
+	for _, o := range broken {
+		if o.source != source {
+			source = o.source
+			fmt.Fprintf(&b, "\n## %s\n", source)
+		}
+
+		summary, details := o.problem()
+		fmt.Fprintf(&b, "\n### Line %d: %s\n\nExample `%s`.\n", o.code.Line, summary, o.code.ID)
+		if details != "" {
+			fence := codeFence(details)
+			fmt.Fprintf(&b, "\n%s\n%s\n%s\n", fence, strings.TrimRight(details, "\n"), fence)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// codeFence returns a backtick fence longer than any run of backticks in text.
+func codeFence(text string) string {
+	fence := "```"
+	for strings.Contains(text, fence) {
+		fence += "`"
+	}
+	return fence
+}
@@ -1,5 +1,5 @@
 import { ref } from 'vue';
-import type { ExecutionResult } from '../types/progress';
+import type { ExecutionResult, RunSettings } from '../types/progress';
 import { executionApi } from '../services/api';
 
 export function useCodeExecution() {
@@ -7,13 +7,13 @@ export function useCodeExecution() {
   const result = ref<ExecutionResult | null>(null);
   const error = ref<string | null>(null);
 
-  const executeCode = async (code: string, snippet: boolean = false) => {
+  const executeCode = async (code: string, settings: RunSettings = {}) => {
     executing.value = true;
     error.value = null;
     result.value = null;
 
     try {
-      result.value = await executionApi.executeCode(code, snippet);
+      result.value = await executionApi.executeCode(code, settings);
       if (result.value.error) {
         error.value = result.value.error;
       }
@@ -51,6 +51,40 @@ export function renderInlineMarkdown(text: string): string {
   return html;
 }
 
+/**
+ * Derives a heading's anchor the way the server does for search links: lower case,
+ * punctuation dropped and spaces turned into hyphens.
+ */
+export function headingAnchor(text: string): string {
+  return text
+    .replace(/!?\[([^\]]*)\]\([^)]*\)/g, '$1')
+    .replace(/[^\p{L}\p{N}\-_ ]/gu, '')
+    .replace(/ /g, '-')
+    .toLowerCase();
+}
+
+/**
+ * Returns a function that gives the headings of one document their anchors in order,
+ * adding -1, -2, ... to anchors that repeat, as the server's HeadingAnchors does.
+ */
+export function createHeadingAnchors(): (text: string) => string {
+  const seen = new Map<string, number>();
+  return (text: string): string => {
+    const anchor = headingAnchor(text);
+    const count = seen.get(anchor) ?? 0;
+    seen.set(anchor, count + 1);
+    return count > 0 ? `${anchor}-${count}` : anchor;
+  };
+}
+
+// Heading classes by level; deeper headings are left as text
+const headingClasses: Record<number, string> = {
+  1: 'text-2xl font-bold text-neutral-900 dark:text-neutral-100 mt-8 mb-4',
+  2: 'text-xl font-bold text-neutral-900 dark:text-neutral-100 mt-8 mb-4',
+  3: 'text-lg font-semibold text-neutral-900 dark:text-neutral-100 mt-6 mb-3',
+  4: 'text-base font-semibold text-neutral-900 dark:text-neutral-100 mt-6 mb-3',
+};
+
 /**
  * Composable that provides markdown rendering functions.
  */
@@ -105,17 +139,21 @@ export function useMarkdownRenderer() {
    * Renders section content markdown, excluding code blocks (which are rendered separately).
    * Handles headings, paragraphs, links, inline code, bold, italic, and lists.
    */
-  const renderSectionContent = (content: string): string => {
+  const renderSectionContent = (content: string, anchors = createHeadingAnchors()): string => {
     let html = content;
 
     // Remove code blocks (they're rendered separately via CodeRunner components)
     html = html.replace(/```[\s\S]*?```/g, '');
 
-    // Convert headers (## becomes h2, ### becomes h3, etc.)
-    html = html.replace(/^#### (.+)$/gm, '<h4 class="text-base font-semibold text-neutral-900 dark:text-neutral-100 mt-6 mb-3">$1</h4>');
-    html = html.replace(/^### (.+)$/gm, '<h3 class="text-lg font-semibold text-neutral-900 dark:text-neutral-100 mt-6 mb-3">$1</h3>');
-    html = html.replace(/^## (.+)$/gm, '<h2 class="text-xl font-bold text-neutral-900 dark:text-neutral-100 mt-8 mb-4">$1</h2>');
-    html = html.replace(/^# (.+)$/gm, '<h1 class="text-2xl font-bold text-neutral-900 dark:text-neutral-100 mt-8 mb-4">$1</h1>');
+    // Convert headers (## becomes h2, ### becomes h3, etc.) in document order, so
+    // repeated headings get the same anchors as on the server and search links find them.
+    // Pass one anchors function for every part of a document rendered in pieces.
+    html = html.replace(/^(#{1,6}) (.+)$/gm, (heading: string, hashes: string, text: string) => {
+      const id = anchors(text);
+      const level = hashes.length;
+      const classes = headingClasses[level];
+      return classes ? `<h${level} id="${id}" class="${classes}">${text}</h${level}>` : heading;
+    });
 
     // Convert markdown links [text](url) to HTML anchors
     html = html.replace(
@@ -13,6 +13,7 @@ export function useTutorial() {
   const loadTutorials = async (forceRefresh = false) => {
     // Check cache first
     if (!forceRefresh) {
+      await cache.syncContentVersion();
       const cached = cache.getCachedMetadata();
       if (cached.length > 0) {
         tutorials.value = cached;
@@ -45,6 +46,7 @@ export function useTutorial() {
   const loadTutorial = async (id: string, forceRefresh = false, instructorMode = false) => {
     // Check cache first (but not for instructor mode - always fetch fresh)
     if (!forceRefresh && !instructorMode) {
+      await cache.syncContentVersion();
       const cached = cache.getCachedTutorial(id);
       if (cached) {
         currentTutorial.value = cached;
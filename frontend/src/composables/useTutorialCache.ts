@@ -1,11 +1,14 @@
 import { ref } from 'vue';
 import type { Tutorial, TutorialMetadata } from '../types/tutorial';
+import { tutorialApi } from '../services/api';
 
 // Simple in-memory cache for tutorials
 const tutorialCache = new Map<string, Tutorial>();
 const metadataCache = ref<TutorialMetadata[]>([]);
 const cacheTimestamp = new Map<string, number>();
 const CACHE_TTL = 5 * 60 * 1000; // 5 minutes
+// Version of the server content the cache holds; the server changes it when tutorials are edited
+let contentVersion: string | null = null;
 
 export function useTutorialCache() {
   const getCachedTutorial = (id: string): Tutorial | null => {
@@ -49,6 +52,19 @@ export function useTutorialCache() {
     cacheTimestamp.delete(id);
   };
 
+  // Clear the cache if the tutorials changed on the server since they were cached
+  const syncContentVersion = async () => {
+    try {
+      const version = await tutorialApi.getContentVersion();
+      if (contentVersion !== null && version !== contentVersion) {
+        clearCache();
+      }
+      contentVersion = version;
+    } catch {
+      // Keep the cache if the version is unavailable
+    }
+  };
+
   return {
     getCachedTutorial,
     setCachedTutorial,
@@ -56,5 +72,6 @@ export function useTutorialCache() {
     setCachedMetadata,
     clearCache,
     clearTutorial,
+    syncContentVersion,
   };
 }
@@ -22,6 +22,18 @@ const router = createRouter({
       component: () => import('../views/TutorialView.vue'),
       props: true,
     },
+    {
+      path: '/cheatsheets/:id',
+      name: 'cheatsheet',
+      component: () => import('../views/CheatsheetView.vue'),
+      props: true,
+    },
+    {
+      path: '/share/:id',
+      name: 'share',
+      component: () => import('../views/ShareView.vue'),
+      props: true,
+    },
     {
       path: '/about',
       name: 'about',
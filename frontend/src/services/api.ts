@@ -1,6 +1,16 @@
 import axios from 'axios';
-import type { Tutorial, TutorialMetadata, Section, Exercise } from '../types/tutorial';
-import type { Progress, ExecutionResult } from '../types/progress';
+import type {
+  Cheatsheet,
+  CheatsheetMetadata,
+  Tutorial,
+  TutorialMetadata,
+  TutorialPrerequisites,
+  Recommendation,
+  SearchHit,
+  Section,
+  Exercise,
+} from '../types/tutorial';
+import type { Progress, ExecutionResult, RunSettings, Share } from '../types/progress';
 
 const API_BASE_URL = import.meta.env.VITE_API_URL || 'http://localhost:8080/api';
 
@@ -29,11 +39,55 @@ export const tutorialApi = {
     const response = await api.get<Section[]>(`/tutorials/${id}/sections`);
     return response.data;
   },
+
+  async getPrerequisites(id: string): Promise<TutorialPrerequisites> {
+    const response = await api.get<TutorialPrerequisites>(`/tutorials/${id}/prerequisites`);
+    return response.data;
+  },
+
+  async getRecommendations(userId: string = 'default'): Promise<Recommendation[]> {
+    const response = await api.get<{ recommendations: Recommendation[] }>('/recommendations', { params: { userId } });
+    return response.data.recommendations;
+  },
+
+  async search(q: string, limit?: number): Promise<SearchHit[]> {
+    const response = await api.get<{ hits: SearchHit[] }>('/search', { params: { q, limit } });
+    return response.data.hits;
+  },
+
+  async getContentVersion(): Promise<string> {
+    const response = await api.get<{ version: string }>('/content/version');
+    return response.data.version;
+  },
+};
+
+export const cheatsheetApi = {
+  async listCheatsheets(): Promise<CheatsheetMetadata[]> {
+    const response = await api.get<CheatsheetMetadata[]>('/cheatsheets');
+    return response.data;
+  },
+
+  async getCheatsheet(id: string): Promise<Cheatsheet> {
+    const response = await api.get<Cheatsheet>(`/cheatsheets/${id}`);
+    return response.data;
+  },
 };
 
 export const executionApi = {
-  async executeCode(code: string, snippet: boolean = false): Promise<ExecutionResult> {
-    const response = await api.post<ExecutionResult>('/execute', { code, snippet });
+  async executeCode(code: string, settings: RunSettings = {}): Promise<ExecutionResult> {
+    const response = await api.post<ExecutionResult>('/execute', { ...settings, code });
+    return response.data;
+  },
+};
+
+export const shareApi = {
+  async createShare(code: string, settings: RunSettings = {}, expiresIn?: string): Promise<Share> {
+    const response = await api.post<Share>('/share', { ...settings, code, expiresIn });
+    return response.data;
+  },
+
+  async getShare(id: string): Promise<Share> {
+    const response = await api.get<Share>(`/share/${id}`);
     return response.data;
   },
 };
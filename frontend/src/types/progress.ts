@@ -1,3 +1,5 @@
+import type { ExpectedFailure, Fixture } from './tutorial';
+
 export interface Progress {
   userId: string;
   completedSections: Record<string, string[]>;
@@ -26,5 +28,68 @@ export interface ExecutionResult {
   error?: string;
   exitCode: number;
   duration: string;
+  styled?: StyledLine[];
+  check?: OutputCheck;
+  failure?: FailureCheck;
+}
+
+export interface RunSettings {
+  snippet?: boolean;
+  toolchain?: string;
+  deterministic?: boolean;
+  insight?: boolean;
+  assembly?: boolean;
+  trace?: boolean;
+  coverage?: boolean;
+  visualize?: boolean;
+  files?: Fixture[];
+  tty?: boolean;
+  columns?: number;
+  timeout?: string;
+  memory?: number;
+  cpu?: number;
+  maxOutput?: number;
+  expect?: OutputExpectation;
+  expectFailure?: ExpectedFailure;
+}
+
+export interface OutputExpectation {
+  output: string;
+  whitespace?: 'trim' | 'exact' | 'collapse';
+  unordered?: boolean;
+}
+
+export interface OutputCheck {
+  passed: boolean;
+  diff?: { op: '=' | '-' | '+'; text: string }[];
+}
+
+export interface FailureCheck {
+  passed: boolean;
+  reason: string;
+}
+
+export interface Share {
+  id: string;
+  code: string;
+  options?: RunSettings;
+  createdAt: string;
+  expiresAt?: string;
+}
+
+export interface StyledSpan {
+  text: string;
+  fg?: string;
+  bg?: string;
+  bold?: boolean;
+  dim?: boolean;
+  italic?: boolean;
+  underline?: boolean;
+  inverse?: boolean;
+  strikethrough?: boolean;
+}
+
+export interface StyledLine {
+  spans: StyledSpan[];
 }
 
@@ -4,6 +4,7 @@ export interface Tutorial {
   duration: string;
   difficulty: string;
   prerequisites: string[];
+  requiredTutorials?: string[];
   sections: Section[];
   level: string;
   tableOfContents?: string;
@@ -15,10 +16,24 @@ export interface TutorialMetadata {
   duration: string;
   difficulty: string;
   prerequisites: string[];
+  requiredTutorials?: string[];
   level: string;
   sectionCount: number;
 }
 
+export interface TutorialPrerequisites {
+  tutorialId: string;
+  prerequisites: string[];
+  required: TutorialMetadata[];
+  all: TutorialMetadata[];
+}
+
+export interface Recommendation {
+  tutorial: TutorialMetadata;
+  reason: 'in-progress' | 'prerequisites-complete' | 'no-prerequisites';
+  completedSections: number;
+}
+
 export interface Section {
   id: string;
   title: string;
@@ -27,7 +42,43 @@ export interface Section {
   teachingPoints: string[] | null;
   order: number;
   content: string;
+  contentHtml?: string;
   instructorNotes?: string;
+  source?: string;
+  duration?: string;
+  objectives?: string[];
+  tags?: string[];
+  prerequisites?: string[];
+  minGoVersion?: string;
+  cheatsheetLinks?: CheatsheetLink[];
+}
+
+export interface CheatsheetLink {
+  topic: string;
+  cheatsheetId: string;
+  entryId: string;
+  title: string;
+}
+
+export interface CheatsheetMetadata {
+  id: string;
+  title: string;
+  entryCount: number;
+}
+
+export interface Cheatsheet {
+  id: string;
+  title: string;
+  intro?: string;
+  entries: CheatsheetEntry[];
+}
+
+export interface CheatsheetEntry {
+  id: string;
+  title: string;
+  group?: string;
+  content: string;
+  codeExamples: CodeExample[];
 }
 
 export interface CodeExample {
@@ -37,7 +88,30 @@ export interface CodeExample {
   runnable: boolean;
   snippet?: boolean;
   expectedOutput?: string;
+  unorderedOutput?: boolean;
   description?: string;
+  fixtures?: Fixture[];
+  toolchain?: string;
+  run?: ExampleRunSettings;
+  expectedFailure?: ExpectedFailure;
+}
+
+export interface ExpectedFailure {
+  kind: 'compile-error' | 'panic' | 'deadlock';
+  message?: string;
+}
+
+export interface ExampleRunSettings {
+  timeout?: string;
+  memory?: number;
+  cpu?: number;
+  maxOutput?: number;
+}
+
+export interface Fixture {
+  name: string;
+  content: string;
+  encoding?: 'base64';
 }
 
 export interface Exercise {
@@ -52,3 +126,19 @@ export interface Exercise {
   expectedOutput?: string;
 }
 
+
+export interface SearchHit {
+  kind: 'section' | 'cheatsheet';
+  tutorialId?: string;
+  sectionId?: string;
+  sectionOrder?: number;
+  cheatsheetId?: string;
+  exampleId?: string;
+  title: string;
+  sectionTitle?: string;
+  heading?: string;
+  anchor?: string;
+  link: string;
+  snippet: string;
+  score: number;
+}
@@ -6,6 +6,12 @@ require gopkg.in/yaml.v3 v3.0.1
 
 require github.com/yuin/goldmark v1.7.13
 
+require github.com/alecthomas/chroma/v2 v2.27.0
+
+require golang.org/x/exp v0.0.0-20260727155853-b88d891fe743
+
+require github.com/dlclark/regexp2/v2 v2.2.1 // indirect
+
 require (
 	github.com/Microsoft/go-winio v0.6.2 // indirect
 	github.com/cespare/xxhash/v2 v2.3.0 // indirect
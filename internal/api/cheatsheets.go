@@ -0,0 +1,52 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/jonesrussell/go-fundamentals-best-practices/pkg/models"
+)
+
+// ListCheatsheets returns all cheatsheets with metadata
+func (h *Handlers) ListCheatsheets(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondMethodNotAllowed(w)
+		return
+	}
+
+	metadata := []models.CheatsheetMetadata{}
+	for _, cheatsheet := range h.content.Cheatsheets() {
+		metadata = append(metadata, models.CheatsheetMetadata{
+			ID:         cheatsheet.ID,
+			Title:      cheatsheet.Title,
+			EntryCount: len(cheatsheet.Entries),
+		})
+	}
+
+	respondJSON(w, h.logger, metadata)
+}
+
+// handleCheatsheetRoutes routes cheatsheet endpoints with path parameters
+func (h *Handlers) handleCheatsheetRoutes(w http.ResponseWriter, r *http.Request) {
+	cheatsheetID := strings.TrimPrefix(r.URL.Path, "/api/cheatsheets/")
+	if cheatsheetID == "" || strings.Contains(cheatsheetID, "/") {
+		respondBadRequest(w, "cheatsheet ID required")
+		return
+	}
+	h.GetCheatsheetByID(w, r, cheatsheetID)
+}
+
+// GetCheatsheetByID returns a cheatsheet with all its entries
+func (h *Handlers) GetCheatsheetByID(w http.ResponseWriter, r *http.Request, cheatsheetID string) {
+	if r.Method != http.MethodGet {
+		respondMethodNotAllowed(w)
+		return
+	}
+
+	cheatsheet := h.content.FindCheatsheet(cheatsheetID)
+	if cheatsheet == nil {
+		http.Error(w, "cheatsheet not found", http.StatusNotFound)
+		return
+	}
+	respondJSON(w, h.logger, cheatsheet)
+}
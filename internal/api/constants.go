@@ -8,4 +8,37 @@ const (
 
 	// ExecuteTimeout is the timeout for code execution requests.
 	ExecuteTimeout = 15 * time.Second
+
+	// JobTimeout is the maximum run time of an asynchronous execution job.
+	JobTimeout = 5 * time.Minute
+
+	// JobTTL is how long a finished job is kept before it expires.
+	JobTTL = 15 * time.Minute
+
+	// MaxJobs is the maximum number of jobs held in memory.
+	MaxJobs = 100
+
+	// MaxConcurrentJobs is the maximum number of jobs running containers at once; the rest queue.
+	MaxConcurrentJobs = 4
+
+	// MaxExecuteBytes is the maximum size of an execution, job or check request, including input files.
+	MaxExecuteBytes = 2 << 20
+
+	// MaxFormatBytes is the maximum size of a format request.
+	MaxFormatBytes = 1 << 20
+
+	// MaxShareBytes is the maximum size of a share request, including input files.
+	MaxShareBytes = 2 << 20
+
+	// MaxShareTTL is the longest lifetime a share can request.
+	MaxShareTTL = 365 * 24 * time.Hour
+
+	// SharePruneInterval is how often expired shares are removed from disk.
+	SharePruneInterval = time.Hour
+
+	// DefaultSearchLimit is the number of search hits returned when no limit is given.
+	DefaultSearchLimit = 20
+
+	// MaxSearchLimit is the most search hits a request can ask for.
+	MaxSearchLimit = 50
 )
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// contentVersionResponse reports the version of the loaded tutorial content
+type contentVersionResponse struct {
+	Version string `json:"version"`
+}
+
+// reloadResponse reports the outcome of a content reload
+type reloadResponse struct {
+	Version   string `json:"version"`
+	Changed   bool   `json:"changed"`
+	Tutorials int    `json:"tutorials"`
+	// Errors holds the tutorials that failed to parse, by ID; their previous versions stay loaded
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+// GetContentVersion returns a hash of the loaded tutorial content, which clients can poll
+// to detect edits
+func (h *Handlers) GetContentVersion(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondMethodNotAllowed(w)
+		return
+	}
+
+	respondJSON(w, h.logger, contentVersionResponse{Version: h.content.Version()})
+}
+
+// ReloadContent re-parses all tutorials from disk
+func (h *Handlers) ReloadContent(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondMethodNotAllowed(w)
+		return
+	}
+	if !h.authorizeAdmin(w, r) {
+		return
+	}
+
+	changed, err := h.content.Reload()
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to reload tutorial content", "error", err)
+		respondInternalError(w, "failed to reload tutorial content")
+		return
+	}
+
+	version := h.content.Version()
+	h.logger.InfoContext(r.Context(), "tutorial content reloaded on request", "version", version, "changed", changed)
+	respondJSON(w, h.logger, reloadResponse{
+		Version:   version,
+		Changed:   changed,
+		Tutorials: len(h.content.Tutorials()),
+		Errors:    h.content.LoadErrors(),
+	})
+}
+
+// authorizeAdmin checks the request carries the admin bearer token, writing an error
+// response when it does not
+func (h *Handlers) authorizeAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if h.adminToken == "" {
+		http.Error(w, "admin endpoints are disabled", http.StatusForbidden)
+		return false
+	}
+
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(h.adminToken)) != 1 {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return false
+	}
+
+	return true
+}
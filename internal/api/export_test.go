@@ -0,0 +1,16 @@
+package api
+
+import (
+	"encoding/json"
+
+	"github.com/jonesrussell/go-fundamentals-best-practices/internal/executor"
+)
+
+// JobOptions decodes an execution request body and returns the options a job runs it with.
+func JobOptions(body string) (executor.RunOptions, error) {
+	var req executeRequest
+	if err := json.Unmarshal([]byte(body), &req); err != nil {
+		return executor.RunOptions{}, err
+	}
+	return req.jobOptions(), nil
+}
@@ -3,11 +3,15 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"time"
 
+	"github.com/jonesrussell/go-fundamentals-best-practices/internal/content"
 	"github.com/jonesrussell/go-fundamentals-best-practices/internal/executor"
+	"github.com/jonesrussell/go-fundamentals-best-practices/internal/jobs"
 	"github.com/jonesrussell/go-fundamentals-best-practices/internal/parser"
 	"github.com/jonesrussell/go-fundamentals-best-practices/internal/storage"
 	"github.com/jonesrussell/go-fundamentals-best-practices/pkg/models"
@@ -15,11 +19,17 @@ import (
 
 // Handlers contains all HTTP handlers
 type Handlers struct {
-	parser    *parser.TutorialParser
-	executor  *executor.CodeExecutor
-	storage   *storage.ProgressStorage
-	tutorials []*models.Tutorial
-	logger    *slog.Logger
+	parser   *parser.TutorialParser
+	executor *executor.CodeExecutor
+	storage  *storage.ProgressStorage
+	shares   *storage.ShareStorage
+	jobs     *jobs.Store
+	content  *content.Library
+	logger   *slog.Logger
+
+	adminToken string
+	stopWatch  context.CancelFunc
+	stopPrune  context.CancelFunc
 }
 
 // NewHandlers creates a new handlers instance
@@ -27,22 +37,71 @@ func NewHandlers(
 	tutorialParser *parser.TutorialParser,
 	codeExecutor *executor.CodeExecutor,
 	progressStorage *storage.ProgressStorage,
+	shareStorage *storage.ShareStorage,
+	contentOpts ...content.Option,
 ) (*Handlers, error) {
 	// Load all tutorials
-	tutorials, err := tutorialParser.LoadAllTutorials()
+	library, err := content.NewLibrary(tutorialParser, slog.Default(), contentOpts...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load tutorials: %w", err)
+		return nil, err
 	}
 
 	return &Handlers{
 		parser:    tutorialParser,
 		executor:  codeExecutor,
 		storage:   progressStorage,
-		tutorials: tutorials,
+		shares:    shareStorage,
+		jobs:      jobs.NewStore(MaxJobs, MaxConcurrentJobs, JobTTL, JobTimeout),
+		content:   library,
 		logger:    slog.Default(),
+		stopWatch: func() {},
+		stopPrune: func() {},
 	}, nil
 }
 
+// WatchContent reloads tutorials whose files change, checking every interval, until Close.
+func (h *Handlers) WatchContent(interval time.Duration) {
+	ctx, cancel := context.WithCancel(context.Background())
+	h.stopWatch = cancel
+	go h.content.Watch(ctx, interval)
+}
+
+// PruneShares removes expired shares every interval, until Close.
+func (h *Handlers) PruneShares(interval time.Duration) {
+	ctx, cancel := context.WithCancel(context.Background())
+	h.stopPrune = cancel
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := h.shares.Prune(); err != nil {
+					h.logger.WarnContext(ctx, "failed to remove expired shares", "error", err)
+				}
+			}
+		}
+	}()
+}
+
+// SetAdminToken sets the bearer token admin endpoints require. Admin endpoints are
+// disabled while no token is set.
+func (h *Handlers) SetAdminToken(token string) {
+	h.adminToken = token
+}
+
+// Close stops watching content and pruning shares, and cancels any running background jobs,
+// waiting for them to stop until ctx is done.
+func (h *Handlers) Close(ctx context.Context) error {
+	h.stopWatch()
+	h.stopPrune()
+	return h.jobs.Close(ctx)
+}
+
 // extractUserID extracts the user ID from the request query params, falling back to default.
 func extractUserID(r *http.Request) string {
 	if userID := r.URL.Query().Get("userId"); userID != "" {
@@ -51,14 +110,9 @@ func extractUserID(r *http.Request) string {
 	return DefaultUserID
 }
 
-// findTutorial looks up a tutorial by ID in the cached tutorials.
+// findTutorial looks up a tutorial by ID in the loaded tutorials.
 func (h *Handlers) findTutorial(tutorialID string) *models.Tutorial {
-	for _, t := range h.tutorials {
-		if t.ID == tutorialID {
-			return t
-		}
-	}
-	return nil
+	return h.content.Find(tutorialID)
 }
 
 // respondNotFound sends a 404 Not Found response.
@@ -85,21 +139,27 @@ func respondInternalError(w http.ResponseWriter, message string) {
 func (h *Handlers) ListTutorials(w http.ResponseWriter, r *http.Request) {
 	var metadata []models.TutorialMetadata
 
-	for _, tutorial := range h.tutorials {
-		metadata = append(metadata, models.TutorialMetadata{
-			ID:            tutorial.ID,
-			Title:         tutorial.Title,
-			Duration:      tutorial.Duration,
-			Difficulty:    tutorial.Difficulty,
-			Prerequisites: tutorial.Prerequisites,
-			Level:         tutorial.Level,
-			SectionCount:  len(tutorial.Sections),
-		})
+	for _, tutorial := range h.content.Tutorials() {
+		metadata = append(metadata, tutorialMetadata(tutorial))
 	}
 
 	respondJSON(w, h.logger, metadata)
 }
 
+// tutorialMetadata summarises a tutorial without its sections
+func tutorialMetadata(tutorial *models.Tutorial) models.TutorialMetadata {
+	return models.TutorialMetadata{
+		ID:            tutorial.ID,
+		Title:         tutorial.Title,
+		Duration:      tutorial.Duration,
+		Difficulty:    tutorial.Difficulty,
+		Prerequisites: tutorial.Prerequisites,
+		RequiredIDs:   tutorial.RequiredIDs,
+		Level:         tutorial.Level,
+		SectionCount:  len(tutorial.Sections),
+	}
+}
+
 // GetTutorialByID returns a full tutorial by ID (path parameter version)
 func (h *Handlers) GetTutorialByID(w http.ResponseWriter, r *http.Request, tutorialID string) {
 	// Check if instructor mode is requested
@@ -112,7 +172,7 @@ func (h *Handlers) GetTutorialByID(w http.ResponseWriter, r *http.Request, tutor
 			respondNotFound(w)
 			return
 		}
-		respondJSON(w, h.logger, tutorial)
+		respondJSON(w, h.logger, h.content.LinkCheatsheets(tutorial))
 		return
 	}
 
@@ -155,6 +215,164 @@ func (h *Handlers) GetTutorialSections(w http.ResponseWriter, r *http.Request) {
 	h.GetTutorialSectionsByID(w, r, tutorialID)
 }
 
+// executeRequest is the request body shared by the synchronous and asynchronous execution endpoints.
+type executeRequest struct {
+	Code string `json:"code"`
+	runSettings
+}
+
+// runSettings are the execution options of a request, which shared snippets store alongside the code
+type runSettings struct {
+	Snippet bool `json:"snippet,omitempty"` // If true, code will be auto-wrapped
+	// Toolchain selects a Go version such as "1.22"; empty uses the server default
+	Toolchain string `json:"toolchain,omitempty"`
+	// Deterministic runs on a virtual clock and returns timestamped output events
+	Deterministic bool `json:"deterministic,omitempty"`
+	// Insight returns escape analysis and inlining decisions; Assembly adds the assembly listing
+	Insight  bool `json:"insight,omitempty"`
+	Assembly bool `json:"assembly,omitempty"`
+	// Trace returns a goroutine timeline recorded with runtime/trace
+	Trace bool `json:"trace,omitempty"`
+	// Coverage returns per-line hit counts from a -cover build
+	Coverage bool `json:"coverage,omitempty"`
+	// Tests is the source of a _test.go file in the code's package; when given, the tests run
+	// with go test instead of the program, and Coverage reports what they covered
+	Tests string `json:"tests,omitempty"`
+	// Visualize returns local variable state after each statement
+	Visualize bool `json:"visualize,omitempty"`
+	// Files are mounted read-only in the program's working directory, e.g. an example's fixtures
+	Files []executor.InputFile `json:"files,omitempty"`
+	// TTY runs the program on a pseudo-terminal Columns wide, so it emits colors
+	TTY     bool `json:"tty,omitempty"`
+	Columns int  `json:"columns,omitempty"`
+	// Timeout, Memory (MB), CPU (percent) and MaxOutput (bytes) override the resource defaults,
+	// up to the server's maximums; a code example's run settings use the same names
+	Timeout   string `json:"timeout,omitempty"`
+	Memory    int    `json:"memory,omitempty"`
+	CPU       int    `json:"cpu,omitempty"`
+	MaxOutput int    `json:"maxOutput,omitempty"`
+	// Expect compares the output with an expected output, e.g. a code example's, and returns pass/fail with a diff
+	Expect *executor.OutputExpectation `json:"expect,omitempty"`
+	// ExpectFailure checks that the program fails to compile, panics or deadlocks, e.g. for an example
+	// that demonstrates an error, and returns whether it failed as expected
+	ExpectFailure *executor.FailureExpectation `json:"expectFailure,omitempty"`
+}
+
+// decodeExecuteRequest decodes and validates an execution request, writing an error response on failure.
+func (h *Handlers) decodeExecuteRequest(w http.ResponseWriter, r *http.Request) (*executeRequest, bool) {
+	var req executeRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, MaxExecuteBytes)).Decode(&req); err != nil {
+		respondBadRequest(w, "invalid request body")
+		return nil, false
+	}
+
+	if !h.validateExecuteRequest(w, &req) {
+		return nil, false
+	}
+
+	return &req, true
+}
+
+// validateExecuteRequest checks the code and options of a request, writing an error response on failure.
+func (h *Handlers) validateExecuteRequest(w http.ResponseWriter, req *executeRequest) bool {
+	if req.Code == "" {
+		respondBadRequest(w, "code is required")
+		return false
+	}
+
+	if err := h.executor.ValidateToolchain(req.Toolchain); err != nil {
+		respondBadRequest(w, err.Error())
+		return false
+	}
+
+	if req.Trace {
+		if err := h.executor.ValidateTrace(req.Toolchain); err != nil {
+			respondBadRequest(w, err.Error())
+			return false
+		}
+	}
+
+	if err := req.runOptions().ValidateTests(); err != nil {
+		respondBadRequest(w, err.Error())
+		return false
+	}
+
+	if err := executor.ValidateInputFiles(req.Files); err != nil {
+		respondBadRequest(w, err.Error())
+		return false
+	}
+
+	if err := req.validateResources(); err != nil {
+		respondBadRequest(w, err.Error())
+		return false
+	}
+
+	if err := req.validateExpectations(); err != nil {
+		respondBadRequest(w, err.Error())
+		return false
+	}
+
+	return true
+}
+
+// validateExpectations checks the expected output and failure, when given
+func (s *runSettings) validateExpectations() error {
+	if s.Expect != nil {
+		if err := s.Expect.Validate(); err != nil {
+			return err
+		}
+	}
+	if s.ExpectFailure != nil {
+		return s.ExpectFailure.Validate()
+	}
+	return nil
+}
+
+// validateResources checks the requested resources are well-formed. Values above the
+// server's maximums are allowed; the executor clamps them.
+func (s *runSettings) validateResources() error {
+	if s.Timeout != "" {
+		if d, err := time.ParseDuration(s.Timeout); err != nil || d <= 0 {
+			return fmt.Errorf("invalid timeout %q, expected a duration such as 5s", s.Timeout)
+		}
+	}
+	if s.Memory < 0 || s.CPU < 0 || s.MaxOutput < 0 {
+		return errors.New("memory, cpu and maxOutput must not be negative")
+	}
+	return nil
+}
+
+// timeout returns the requested timeout, or zero for the server default.
+func (s *runSettings) timeout() time.Duration {
+	d, _ := time.ParseDuration(s.Timeout) // Validated by validateResources
+	return d
+}
+
+// runOptions converts the request into executor options. The timeout is clamped to
+// ExecuteTimeout, which bounds the whole request.
+func (req *executeRequest) runOptions() executor.RunOptions {
+	return executor.RunOptions{
+		Snippet:       req.Snippet,
+		Toolchain:     req.Toolchain,
+		Deterministic: req.Deterministic,
+		Insight:       req.Insight,
+		Assembly:      req.Assembly,
+		Trace:         req.Trace,
+		Coverage:      req.Coverage,
+		Tests:         req.Tests,
+		Visualize:     req.Visualize,
+		Files:         req.Files,
+		TTY:           req.TTY,
+		Columns:       req.Columns,
+		Timeout:       min(req.timeout(), ExecuteTimeout),
+		MemoryMB:      req.Memory,
+		CPUPercent:    req.CPU,
+		MaxOutput:     req.MaxOutput,
+		Expect:        req.Expect,
+		ExpectFailure: req.ExpectFailure,
+	}
+}
+
 // ExecuteCode executes Go code and returns the result
 func (h *Handlers) ExecuteCode(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -162,39 +380,83 @@ func (h *Handlers) ExecuteCode(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var req struct {
-		Code    string `json:"code"`
-		Snippet bool   `json:"snippet,omitempty"` // If true, code will be auto-wrapped
+	req, ok := h.decodeExecuteRequest(w, r)
+	if !ok {
+		return
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondBadRequest(w, "invalid request body")
+	ctx, cancel := context.WithTimeout(r.Context(), ExecuteTimeout)
+	defer cancel()
+
+	result, err := h.executor.Run(ctx, req.Code, req.runOptions())
+	if err != nil {
+		respondInternalError(w, fmt.Sprintf("execution error: %v", err))
 		return
 	}
 
-	if req.Code == "" {
-		respondBadRequest(w, "code is required")
+	respondJSON(w, h.logger, result)
+}
+
+// CheckCode vets Go code without running it
+func (h *Handlers) CheckCode(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondMethodNotAllowed(w)
+		return
+	}
+
+	req, ok := h.decodeExecuteRequest(w, r)
+	if !ok {
 		return
 	}
 
 	ctx, cancel := context.WithTimeout(r.Context(), ExecuteTimeout)
 	defer cancel()
 
-	// Use appropriate execution method based on snippet flag
-	var result *executor.ExecutionResult
-	var err error
-	if req.Snippet {
-		result, err = h.executor.ExecuteSnippet(ctx, req.Code)
-	} else {
-		result, err = h.executor.Execute(ctx, req.Code)
+	result, err := h.executor.Check(ctx, req.Code, req.runOptions())
+	if err != nil {
+		respondInternalError(w, fmt.Sprintf("check error: %v", err))
+		return
+	}
+
+	respondJSON(w, h.logger, result)
+}
+
+// formatRequest is the body of a format request
+type formatRequest struct {
+	Code string `json:"code"`
+}
+
+// FormatCode formats Go code with gofmt rules
+func (h *Handlers) FormatCode(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondMethodNotAllowed(w)
+		return
 	}
 
+	// Formatting needs only the code, so none of the execution options are validated
+	var req formatRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, MaxFormatBytes)).Decode(&req); err != nil {
+		respondBadRequest(w, "invalid request body")
+		return
+	}
+	if req.Code == "" {
+		respondBadRequest(w, "code is required")
+		return
+	}
+
+	// Syntax errors are reported in the body, like the Go playground does
+	formatted, err := executor.Format(req.Code)
 	if err != nil {
-		respondInternalError(w, fmt.Sprintf("execution error: %v", err))
+		respondJSON(w, h.logger, map[string]string{"error": err.Error()})
 		return
 	}
 
-	respondJSON(w, h.logger, result)
+	respondJSON(w, h.logger, map[string]string{"code": formatted})
+}
+
+// ListToolchains returns the Go toolchains available for execution
+func (h *Handlers) ListToolchains(w http.ResponseWriter, _ *http.Request) {
+	respondJSON(w, h.logger, h.executor.Toolchains())
 }
 
 // GetProgress returns user progress
@@ -291,8 +553,13 @@ func (h *Handlers) GetExercises(w http.ResponseWriter, r *http.Request) {
 
 // respondJSON sends a JSON response with 200 OK status
 func respondJSON(w http.ResponseWriter, logger *slog.Logger, data any) {
+	respondJSONStatus(w, logger, http.StatusOK, data)
+}
+
+// respondJSONStatus sends a JSON response with the given status code
+func respondJSONStatus(w http.ResponseWriter, logger *slog.Logger, status int, data any) {
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
+	w.WriteHeader(status)
 	if err := json.NewEncoder(w).Encode(data); err != nil {
 		logger.Error("failed to encode JSON response", "error", err)
 	}
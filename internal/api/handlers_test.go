@@ -0,0 +1,39 @@
+package api_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jonesrussell/go-fundamentals-best-practices/internal/api"
+	"github.com/jonesrussell/go-fundamentals-best-practices/internal/parser"
+)
+
+// TestFormatCode checks that formatting validates only the code and caps the request body
+func TestFormatCode(t *testing.T) {
+	handlers, err := api.NewHandlers(parser.NewTutorialParser(t.TempDir()), nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oversized := `{"code":"` + strings.Repeat("x", api.MaxFormatBytes) + `"}`
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{"formats code", `{"code":"package main\nfunc main(){}","toolchain":"unknown"}`, http.StatusOK},
+		{"missing code", `{}`, http.StatusBadRequest},
+		{"oversized body", oversized, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handlers.FormatCode(w, httptest.NewRequest(http.MethodPost, "/api/format", strings.NewReader(tt.body)))
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.status, w.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/jonesrussell/go-fundamentals-best-practices/internal/executor"
+	"github.com/jonesrussell/go-fundamentals-best-practices/internal/jobs"
+)
+
+// SubmitJob starts an asynchronous execution and returns the job ID
+func (h *Handlers) SubmitJob(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondMethodNotAllowed(w)
+		return
+	}
+
+	req, ok := h.decodeExecuteRequest(w, r)
+	if !ok {
+		return
+	}
+
+	opts := req.jobOptions()
+	job, err := h.jobs.Submit(func(ctx context.Context) (*executor.ExecutionResult, error) {
+		return h.executor.Run(ctx, req.Code, opts)
+	})
+	if err != nil {
+		if errors.Is(err, jobs.ErrStoreFull) {
+			http.Error(w, "too many jobs, try again later", http.StatusServiceUnavailable)
+			return
+		}
+		if errors.Is(err, jobs.ErrStoreClosed) {
+			http.Error(w, "server is shutting down", http.StatusServiceUnavailable)
+			return
+		}
+		respondInternalError(w, "failed to start job")
+		return
+	}
+
+	h.logger.Info("execution job submitted", "job_id", job.ID)
+	respondJSONStatus(w, h.logger, http.StatusAccepted, job)
+}
+
+// jobOptions converts the request into executor options for a job. Jobs run for as long as
+// they ask, up to JobTimeout, which replaces the executor's sync timeout limit.
+func (req *executeRequest) jobOptions() executor.RunOptions {
+	opts := req.runOptions()
+	opts.Timeout = min(cmp.Or(req.timeout(), JobTimeout), JobTimeout)
+	opts.MaxTimeout = JobTimeout
+	return opts
+}
+
+// GetJob returns the status and, once finished, the result of a job
+func (h *Handlers) GetJob(w http.ResponseWriter, _ *http.Request, jobID string) {
+	job, ok := h.jobs.Get(jobID)
+	if !ok {
+		http.Error(w, "job not found", http.StatusNotFound)
+		return
+	}
+
+	respondJSON(w, h.logger, job)
+}
+
+// CancelJob cancels a running job and kills its container
+func (h *Handlers) CancelJob(w http.ResponseWriter, _ *http.Request, jobID string) {
+	job, ok := h.jobs.Cancel(jobID)
+	if !ok {
+		http.Error(w, "job not found", http.StatusNotFound)
+		return
+	}
+
+	h.logger.Info("execution job canceled", "job_id", job.ID)
+	respondJSON(w, h.logger, job)
+}
@@ -0,0 +1,31 @@
+package api_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jonesrussell/go-fundamentals-best-practices/internal/api"
+)
+
+// TestJobOptionsTimeout checks that a job asking for longer than the sync timeout keeps its
+// timeout, with JobTimeout as its limit.
+func TestJobOptionsTimeout(t *testing.T) {
+	tests := []struct {
+		body string
+		want time.Duration
+	}{
+		{body: `{"code": "package main", "timeout": "1m"}`, want: time.Minute},
+		{body: `{"code": "package main"}`, want: api.JobTimeout},
+		{body: `{"code": "package main", "timeout": "1h"}`, want: api.JobTimeout},
+	}
+
+	for _, tt := range tests {
+		opts, err := api.JobOptions(tt.body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if opts.Timeout != tt.want || opts.MaxTimeout != api.JobTimeout {
+			t.Errorf("%s: timeout %v, limit %v; want %v, limit %v", tt.body, opts.Timeout, opts.MaxTimeout, tt.want, api.JobTimeout)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/jonesrussell/go-fundamentals-best-practices/pkg/models"
+)
+
+// prerequisitesResponse lists what a tutorial requires
+type prerequisitesResponse struct {
+	TutorialID    string                    `json:"tutorialId"`
+	Prerequisites []string                  `json:"prerequisites"` // Free-text prerequisites
+	Required      []models.TutorialMetadata `json:"required"`      // Tutorials it requires directly
+	All           []models.TutorialMetadata `json:"all"`           // Every tutorial it requires, in the order to take them
+}
+
+// recommendation is a tutorial a user could take next. The reason is "in-progress",
+// "prerequisites-complete" or "no-prerequisites".
+type recommendation struct {
+	Tutorial          models.TutorialMetadata `json:"tutorial"`
+	Reason            string                  `json:"reason"`
+	CompletedSections int                     `json:"completedSections"`
+}
+
+// recommendationsResponse lists the tutorials a user could take next, best first
+type recommendationsResponse struct {
+	UserID          string           `json:"userId"`
+	Recommendations []recommendation `json:"recommendations"`
+}
+
+// GetTutorialPrerequisites returns the tutorials a tutorial requires
+func (h *Handlers) GetTutorialPrerequisites(w http.ResponseWriter, r *http.Request, tutorialID string) {
+	if r.Method != http.MethodGet {
+		respondMethodNotAllowed(w)
+		return
+	}
+
+	tutorial := h.findTutorial(tutorialID)
+	if tutorial == nil {
+		respondNotFound(w)
+		return
+	}
+
+	resp := prerequisitesResponse{
+		TutorialID:    tutorial.ID,
+		Prerequisites: tutorial.Prerequisites,
+		Required:      []models.TutorialMetadata{},
+		All:           []models.TutorialMetadata{},
+	}
+	for _, id := range tutorial.RequiredIDs {
+		if required := h.findTutorial(id); required != nil {
+			resp.Required = append(resp.Required, tutorialMetadata(required))
+		}
+	}
+	for _, required := range h.content.Prerequisites(tutorial.ID) {
+		resp.All = append(resp.All, tutorialMetadata(required))
+	}
+
+	respondJSON(w, h.logger, resp)
+}
+
+// GetRecommendations suggests the tutorials a user could take next based on their progress
+func (h *Handlers) GetRecommendations(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondMethodNotAllowed(w)
+		return
+	}
+
+	userID := extractUserID(r)
+	resp := recommendationsResponse{UserID: userID, Recommendations: []recommendation{}}
+	for _, rec := range h.content.Recommend(h.storage.GetProgress(userID)) {
+		resp.Recommendations = append(resp.Recommendations, recommendation{
+			Tutorial:          tutorialMetadata(rec.Tutorial),
+			Reason:            rec.Reason,
+			CompletedSections: rec.CompletedSections,
+		})
+	}
+
+	respondJSON(w, h.logger, resp)
+}
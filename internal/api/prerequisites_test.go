@@ -0,0 +1,80 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jonesrussell/go-fundamentals-best-practices/internal/api"
+	"github.com/jonesrussell/go-fundamentals-best-practices/internal/parser"
+	"github.com/jonesrussell/go-fundamentals-best-practices/internal/storage"
+)
+
+// writeTutorial writes a one-section tutorial with the given prerequisites under dir.
+func writeTutorial(t *testing.T, dir, id string, prerequisites ...string) {
+	t.Helper()
+	tutorialDir := filepath.Join(dir, "tutorial-"+id)
+	if err := os.MkdirAll(filepath.Join(tutorialDir, "sections"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	config := "id: \"" + id + "\"\ntitle: \"Tutorial " + id + "\"\nprerequisites:\n"
+	for _, p := range prerequisites {
+		config += "  - \"" + p + "\"\n"
+	}
+	files := map[string]string{
+		"tutorial.yaml":        config,
+		"sections/01-intro.md": "# Introduction\n\nText.\n",
+	}
+	for name, text := range files {
+		if err := os.WriteFile(filepath.Join(tutorialDir, name), []byte(text), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// TestGetRecommendations checks that free-text prerequisites are ignored, and that a tutorial
+// is recommended once the tutorials it requires are finished
+func TestGetRecommendations(t *testing.T) {
+	dir := t.TempDir()
+	writeTutorial(t, dir, "1", "Basic programming concepts helpful but not required")
+	writeTutorial(t, dir, "2", "Go Basics", "tutorial-1")
+	writeTutorial(t, dir, "3", "Structs", "tutorial-2")
+
+	progress, err := storage.NewProgressStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := progress.MarkSectionComplete("learner", "1", "intro"); err != nil {
+		t.Fatal(err)
+	}
+
+	handlers, err := api.NewHandlers(parser.NewTutorialParser(dir), nil, progress, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	handlers.GetRecommendations(w, httptest.NewRequest(http.MethodGet, "/api/recommendations?userId=learner", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	var resp struct {
+		Recommendations []struct {
+			Tutorial struct {
+				ID string `json:"id"`
+			} `json:"tutorial"`
+			Reason string `json:"reason"`
+		} `json:"recommendations"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Recommendations) != 1 || resp.Recommendations[0].Tutorial.ID != "2" ||
+		resp.Recommendations[0].Reason != "prerequisites-complete" {
+		t.Errorf("recommendations = %+v, want tutorial 2 with its prerequisites complete", resp.Recommendations)
+	}
+}
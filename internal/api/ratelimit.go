@@ -0,0 +1,245 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Rate limit budget names.
+const (
+	budgetExecute = "execute"
+	budgetCheck   = "check"
+	budgetFormat  = "format"
+	budgetShare   = "share"
+)
+
+// bucketSweepInterval is how often idle buckets are dropped from memory.
+const bucketSweepInterval = time.Minute
+
+// errInvalidRateBudget is returned when a rate budget string cannot be parsed.
+var errInvalidRateBudget = errors.New("invalid rate budget, expected <requests>/<duration> such as 20/1m")
+
+// RateBudget is a token bucket: Requests tokens that refill completely over Per.
+type RateBudget struct {
+	Requests int
+	Per      time.Duration
+}
+
+// RateLimitConfig configures the per-endpoint rate limit budgets.
+type RateLimitConfig struct {
+	Execute RateBudget
+	Check   RateBudget
+	Format  RateBudget
+	Share   RateBudget
+	// TrustProxy keys clients by the address the proxy in front of the server appended to
+	// X-Forwarded-For, its last entry, instead of the peer address.
+	TrustProxy bool
+}
+
+// DefaultRateLimitConfig returns the default rate limit budgets.
+func DefaultRateLimitConfig() RateLimitConfig {
+	return RateLimitConfig{
+		Execute: RateBudget{Requests: 20, Per: time.Minute},
+		Check:   RateBudget{Requests: 30, Per: time.Minute},
+		Format:  RateBudget{Requests: 120, Per: time.Minute},
+		Share:   RateBudget{Requests: 10, Per: time.Minute},
+	}
+}
+
+// ParseRateBudget parses a budget such as "20/1m" (20 requests per minute).
+func ParseRateBudget(value string) (RateBudget, error) {
+	requests, per, found := strings.Cut(value, "/")
+	if !found {
+		return RateBudget{}, errInvalidRateBudget
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(requests))
+	if err != nil || n <= 0 {
+		return RateBudget{}, errInvalidRateBudget
+	}
+
+	d, err := time.ParseDuration(strings.TrimSpace(per))
+	if err != nil || d <= 0 {
+		return RateBudget{}, errInvalidRateBudget
+	}
+
+	return RateBudget{Requests: n, Per: d}, nil
+}
+
+// bucket is the token state for one client.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// limiter applies one budget to many clients.
+type limiter struct {
+	mu        sync.Mutex
+	budget    RateBudget
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// newLimiter creates a limiter for a budget.
+func newLimiter(budget RateBudget) *limiter {
+	return &limiter{
+		budget:    budget,
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+	}
+}
+
+// refillRate returns the number of tokens added per second.
+func (l *limiter) refillRate() float64 {
+	return float64(l.budget.Requests) / l.budget.Per.Seconds()
+}
+
+// allow takes a token for key. It returns the remaining tokens, the time until the
+// bucket is full again, and whether the request is allowed.
+func (l *limiter) allow(key string, now time.Time) (remaining int, reset time.Duration, allowed bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweepLocked(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(l.budget.Requests), last: now}
+		l.buckets[key] = b
+	}
+
+	rate := l.refillRate()
+	b.tokens = math.Min(float64(l.budget.Requests), b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		allowed = true
+	}
+
+	missing := float64(l.budget.Requests) - b.tokens
+	reset = time.Duration(missing / rate * float64(time.Second))
+
+	return int(b.tokens), reset, allowed
+}
+
+// sweepLocked drops buckets that have refilled completely, bounding memory use.
+// Must be called with the lock held.
+func (l *limiter) sweepLocked(now time.Time) {
+	if now.Sub(l.lastSweep) < bucketSweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	for key, b := range l.buckets {
+		if now.Sub(b.last) >= l.budget.Per {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// RateLimiter is middleware that throttles the endpoints that launch containers or store data.
+type RateLimiter struct {
+	limiters   map[string]*limiter
+	routes     map[string]string // path -> budget name
+	trustProxy bool
+	logger     *slog.Logger
+}
+
+// NewRateLimiter creates a rate limiter with separate budgets for execute, check, format and share endpoints.
+func NewRateLimiter(cfg RateLimitConfig, logger *slog.Logger) *RateLimiter {
+	return &RateLimiter{
+		limiters: map[string]*limiter{
+			budgetExecute: newLimiter(cfg.Execute),
+			budgetCheck:   newLimiter(cfg.Check),
+			budgetFormat:  newLimiter(cfg.Format),
+			budgetShare:   newLimiter(cfg.Share),
+		},
+		routes: map[string]string{
+			"/api/execute": budgetExecute,
+			"/api/jobs":    budgetExecute,
+			"/api/check":   budgetCheck,
+			"/api/format":  budgetFormat,
+			"/api/share":   budgetShare,
+		},
+		trustProxy: cfg.TrustProxy,
+		logger:     logger,
+	}
+}
+
+// Middleware wraps next with rate limiting. Only POST requests to limited routes consume tokens.
+func (rl *RateLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		budget, limited := rl.routes[r.URL.Path]
+		if !limited || r.Method != http.MethodPost {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		l := rl.limiters[budget]
+		key := rl.clientKey(r)
+		remaining, reset, allowed := l.allow(key, time.Now())
+
+		w.Header().Set("RateLimit-Limit", strconv.Itoa(l.budget.Requests))
+		w.Header().Set("RateLimit-Remaining", strconv.Itoa(remaining))
+		w.Header().Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(reset)))
+
+		if !allowed {
+			retryAfter := time.Duration(float64(time.Second) / l.refillRate())
+			w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(retryAfter)))
+			rl.logger.Warn("rate limit exceeded",
+				"client", key,
+				"budget", budget,
+				"path", r.URL.Path,
+			)
+			http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// clientKey identifies the client by its IP. Request parameters such as userId are not
+// authenticated, so keying on them would let a client pick a fresh budget for every request.
+func (rl *RateLimiter) clientKey(r *http.Request) string {
+	return "ip:" + rl.clientIP(r)
+}
+
+// clientIP returns the client address, honouring X-Forwarded-For only when the proxy is trusted.
+// Clients can send X-Forwarded-For themselves, so only the last hop, which the trusted proxy
+// appended, is used.
+func (rl *RateLimiter) clientIP(r *http.Request) string {
+	if rl.trustProxy {
+		if forwarded := r.Header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+			hops := strings.Split(forwarded[len(forwarded)-1], ",")
+			if last := strings.TrimSpace(hops[len(hops)-1]); last != "" {
+				return last
+			}
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// ceilSeconds rounds a duration up to whole seconds.
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// String formats a budget in the form accepted by ParseRateBudget.
+func (b RateBudget) String() string {
+	return fmt.Sprintf("%d/%s", b.Requests, b.Per)
+}
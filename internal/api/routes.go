@@ -12,9 +12,27 @@ func (h *Handlers) SetupRoutes() *http.ServeMux {
 	// Tutorial endpoints
 	mux.HandleFunc("/api/tutorials", h.ListTutorials)
 	mux.HandleFunc("/api/tutorials/", h.handleTutorialRoutes)
+	mux.HandleFunc("/api/content/version", h.GetContentVersion)
+	mux.HandleFunc("/api/recommendations", h.GetRecommendations)
+	mux.HandleFunc("/api/search", h.Search)
+
+	// Cheatsheets
+	mux.HandleFunc("/api/cheatsheets", h.ListCheatsheets)
+	mux.HandleFunc("/api/cheatsheets/", h.handleCheatsheetRoutes)
 
 	// Code execution
 	mux.HandleFunc("/api/execute", h.ExecuteCode)
+	mux.HandleFunc("/api/check", h.CheckCode)
+	mux.HandleFunc("/api/format", h.FormatCode)
+	mux.HandleFunc("/api/toolchains", h.ListToolchains)
+
+	// Shared snippets
+	mux.HandleFunc("/api/share", h.CreateShare)
+	mux.HandleFunc("/api/share/", h.handleShareRoutes)
+
+	// Asynchronous execution jobs
+	mux.HandleFunc("/api/jobs", h.SubmitJob)
+	mux.HandleFunc("/api/jobs/", h.handleJobRoutes)
 
 	// Progress tracking
 	mux.HandleFunc("/api/progress", func(w http.ResponseWriter, r *http.Request) {
@@ -32,6 +50,9 @@ func (h *Handlers) SetupRoutes() *http.ServeMux {
 	// Exercises
 	mux.HandleFunc("/api/exercises/", h.handleExerciseRoutes)
 
+	// Administration
+	mux.HandleFunc("/api/admin/reload", h.ReloadContent)
+
 	return mux
 }
 
@@ -52,6 +73,11 @@ func (h *Handlers) handleTutorialRoutes(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if len(parts) > 1 && parts[1] == "prerequisites" {
+		h.GetTutorialPrerequisites(w, r, tutorialID)
+		return
+	}
+
 	// Default: get full tutorial
 	h.GetTutorialByID(w, r, tutorialID)
 }
@@ -70,12 +96,31 @@ func (h *Handlers) handleExerciseRoutes(w http.ResponseWriter, r *http.Request)
 	h.GetExercisesByTutorialID(w, r, tutorialID)
 }
 
+// handleJobRoutes routes job endpoints with path parameters
+func (h *Handlers) handleJobRoutes(w http.ResponseWriter, r *http.Request) {
+	jobID := strings.TrimPrefix(r.URL.Path, "/api/jobs/")
+	if jobID == "" || strings.Contains(jobID, "/") {
+		respondBadRequest(w, "job ID required")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.GetJob(w, r, jobID)
+	case http.MethodDelete:
+		h.CancelJob(w, r, jobID)
+	default:
+		respondMethodNotAllowed(w)
+	}
+}
+
 // CORSMiddleware is a CORS middleware
 func CORSMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Expose-Headers", "RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset, Retry-After")
 
 		if r.Method == http.MethodOptions {
 			w.WriteHeader(http.StatusOK)
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/jonesrussell/go-fundamentals-best-practices/internal/search"
+)
+
+// searchResponse lists the hits for a search query, best first
+type searchResponse struct {
+	Query string       `json:"query"`
+	Hits  []search.Hit `json:"hits"`
+}
+
+// Search finds sections and cheatsheets matching the q query parameter. The limit parameter
+// caps the number of hits.
+func (h *Handlers) Search(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondMethodNotAllowed(w)
+		return
+	}
+
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		respondBadRequest(w, "search query required")
+		return
+	}
+
+	limit := DefaultSearchLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			respondBadRequest(w, "limit must be a positive number")
+			return
+		}
+		limit = min(n, MaxSearchLimit)
+	}
+
+	hits := h.content.Search(query, limit)
+	if hits == nil {
+		hits = []search.Hit{}
+	}
+	respondJSON(w, h.logger, searchResponse{Query: query, Hits: hits})
+}
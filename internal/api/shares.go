@@ -0,0 +1,106 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jonesrussell/go-fundamentals-best-practices/internal/storage"
+)
+
+// shareRequest is the request body for sharing code: an execution request plus an optional lifetime
+type shareRequest struct {
+	executeRequest
+	// ExpiresIn is a duration such as "72h"; empty keeps the share forever
+	ExpiresIn string `json:"expiresIn,omitempty"`
+}
+
+// CreateShare stores code and its execution options under a permalink
+func (h *Handlers) CreateShare(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondMethodNotAllowed(w)
+		return
+	}
+
+	var req shareRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, MaxShareBytes)).Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, fmt.Sprintf("share exceeds %d bytes", MaxShareBytes), http.StatusRequestEntityTooLarge)
+			return
+		}
+		respondBadRequest(w, "invalid request body")
+		return
+	}
+
+	if !h.validateExecuteRequest(w, &req.executeRequest) {
+		return
+	}
+
+	ttl, err := parseShareTTL(req.ExpiresIn)
+	if err != nil {
+		respondBadRequest(w, err.Error())
+		return
+	}
+
+	options, err := json.Marshal(req.runSettings)
+	if err != nil {
+		respondInternalError(w, "failed to encode options")
+		return
+	}
+
+	share, err := h.shares.Save(req.Code, options, ttl)
+	if err != nil {
+		respondInternalError(w, fmt.Sprintf("failed to save share: %v", err))
+		return
+	}
+
+	h.logger.Info("code shared", "share_id", share.ID)
+	respondJSONStatus(w, h.logger, http.StatusCreated, share)
+}
+
+// GetShare returns shared code and its execution options
+func (h *Handlers) GetShare(w http.ResponseWriter, _ *http.Request, shareID string) {
+	share, err := h.shares.Get(shareID)
+	if errors.Is(err, storage.ErrShareNotFound) {
+		http.Error(w, "share not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		respondInternalError(w, fmt.Sprintf("failed to load share: %v", err))
+		return
+	}
+
+	respondJSON(w, h.logger, share)
+}
+
+// handleShareRoutes routes share endpoints with path parameters
+func (h *Handlers) handleShareRoutes(w http.ResponseWriter, r *http.Request) {
+	shareID := strings.TrimPrefix(r.URL.Path, "/api/share/")
+	if shareID == "" || strings.Contains(shareID, "/") {
+		respondBadRequest(w, "share ID required")
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		respondMethodNotAllowed(w)
+		return
+	}
+	h.GetShare(w, r, shareID)
+}
+
+// parseShareTTL parses the requested share lifetime, which must be positive and at most MaxShareTTL
+func parseShareTTL(expiresIn string) (time.Duration, error) {
+	if expiresIn == "" {
+		return 0, nil
+	}
+
+	ttl, err := time.ParseDuration(expiresIn)
+	if err != nil || ttl <= 0 || ttl > MaxShareTTL {
+		return 0, fmt.Errorf("expiresIn must be a duration between 1s and %s", MaxShareTTL)
+	}
+	return ttl, nil
+}
@@ -0,0 +1,115 @@
+package content
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/jonesrussell/go-fundamentals-best-practices/pkg/models"
+)
+
+// topicLinkText matches a Markdown link in a topic, capturing the text shown.
+var topicLinkText = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+
+// pluralSuffixMinLength is the shortest word whose trailing "s" is dropped when matching,
+// so "channels" matches "channel" but "is" stays as it is.
+const pluralSuffixMinLength = 4
+
+// entryTerms is a cheatsheet entry with the words of its title.
+type entryTerms struct {
+	link  models.CheatsheetLink
+	words []string
+}
+
+// cheatsheetLinker finds the cheatsheet entries a section topic is about.
+type cheatsheetLinker []entryTerms
+
+// newCheatsheetLinker prepares the entries of the cheatsheets for matching. Titles that more
+// than one cheatsheet uses, such as "Best Practices", are too general to link to.
+func newCheatsheetLinker(cheatsheets []*models.Cheatsheet) cheatsheetLinker {
+	sheetsWithTitle := make(map[string]int)
+	for _, sheet := range cheatsheets {
+		titles := make(map[string]bool)
+		for _, entry := range sheet.Entries {
+			titles[strings.ToLower(entry.Title)] = true
+		}
+		for title := range titles {
+			sheetsWithTitle[title]++
+		}
+	}
+
+	var linker cheatsheetLinker
+	for _, sheet := range cheatsheets {
+		for _, entry := range sheet.Entries {
+			if sheetsWithTitle[strings.ToLower(entry.Title)] > 1 {
+				continue
+			}
+			linker = append(linker, entryTerms{
+				link:  models.CheatsheetLink{CheatsheetID: sheet.ID, EntryID: entry.ID, Title: entry.Title},
+				words: matchWords(entry.Title),
+			})
+		}
+	}
+	return linker
+}
+
+// link returns a copy of the tutorial whose sections link their topics to cheatsheet entries.
+// The tutorial itself is left alone, since readers may be using it.
+func (c cheatsheetLinker) link(t *models.Tutorial) *models.Tutorial {
+	linked := *t
+	linked.Sections = slices.Clone(t.Sections)
+	for i := range linked.Sections {
+		c.linkSection(&linked.Sections[i])
+	}
+	return &linked
+}
+
+// linkSection sets the cheatsheet links of a section. A topic links to the entries whose
+// title words all appear in it, keeping only the most specific: "Channel direction" links to
+// "Channel Direction" and not also to "Channels".
+func (c cheatsheetLinker) linkSection(section *models.Section) {
+	section.CheatsheetLinks = nil
+	for _, topic := range section.Topics {
+		words := matchWords(topic)
+		var best []models.CheatsheetLink
+		bestLength := 0
+		for _, entry := range c {
+			if len(entry.words) == 0 || len(entry.words) < bestLength || !containsAll(words, entry.words) {
+				continue
+			}
+			if len(entry.words) > bestLength {
+				best, bestLength = nil, len(entry.words)
+			}
+			link := entry.link
+			link.Topic = topic
+			best = append(best, link)
+		}
+		section.CheatsheetLinks = append(section.CheatsheetLinks, best...)
+	}
+}
+
+// matchWords returns the lower case words of Markdown text, without link targets or
+// plural endings. Qualified names are split, so "sync.WaitGroup" gives "sync" and "waitgroup".
+func matchWords(text string) []string {
+	text = topicLinkText.ReplaceAllString(text, "$1")
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, word := range words {
+		if len(word) >= pluralSuffixMinLength {
+			words[i] = strings.TrimSuffix(word, "s")
+		}
+	}
+	return words
+}
+
+// containsAll reports whether every word in want is in words.
+func containsAll(words, want []string) bool {
+	for _, w := range want {
+		if !slices.Contains(words, w) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,17 @@
+package content
+
+import (
+	"log/slog"
+
+	"github.com/jonesrussell/go-fundamentals-best-practices/pkg/models"
+)
+
+// NewTestLibrary returns a library holding tutorials and their prerequisite graph, as a reload
+// would leave it.
+func NewTestLibrary(tutorials []*models.Tutorial) (*Library, error) {
+	graph, err := newPrerequisiteGraph(tutorials, slog.New(slog.DiscardHandler))
+	if err != nil {
+		return nil, err
+	}
+	return &Library{tutorials: tutorials, graph: graph}, nil
+}
@@ -0,0 +1,364 @@
+// Package content keeps the parsed tutorials in memory and reloads them when their files change.
+package content
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io/fs"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jonesrussell/go-fundamentals-best-practices/internal/parser"
+	"github.com/jonesrussell/go-fundamentals-best-practices/internal/search"
+	"github.com/jonesrussell/go-fundamentals-best-practices/pkg/models"
+)
+
+// versionLength is the number of hex characters of the content hash used as the version.
+const versionLength = 16
+
+// sharedFiles is the stamp key of files that belong to no single tutorial, such as the
+// legacy fixtures directory. A change to them reloads every tutorial.
+const sharedFiles = ""
+
+// cheatsheetFiles is the stamp key of the cheatsheets, which cannot clash with a tutorial ID.
+const cheatsheetFiles = "\x00cheatsheets"
+
+// Option configures a Library.
+type Option func(*Library)
+
+// WithCheatsheets loads the Markdown cheatsheets in dir alongside the tutorials.
+func WithCheatsheets(dir string) Option {
+	return func(l *Library) {
+		l.cheatsheetsDir = dir
+	}
+}
+
+// Library holds the parsed tutorials. Reloads parse into a new slice and swap it in,
+// so readers always see a complete set of tutorials.
+type Library struct {
+	parser         *parser.TutorialParser
+	dir            string
+	cheatsheetsDir string
+	logger         *slog.Logger
+
+	reloadMu sync.Mutex        // Serializes reloads
+	stamps   map[string]string // Tutorial ID -> fingerprint of its files' sizes and modification times
+
+	mu          sync.RWMutex
+	tutorials   []*models.Tutorial
+	cheatsheets []*models.Cheatsheet
+	linker      cheatsheetLinker
+	graph       *prerequisiteGraph
+	index       *search.Index
+	version     string
+	loadErrors  map[string]string // Tutorial ID -> error of the last failed parse
+}
+
+// NewLibrary loads all tutorials from the parser's tutorials directory.
+func NewLibrary(tutorialParser *parser.TutorialParser, logger *slog.Logger, opts ...Option) (*Library, error) {
+	l := &Library{
+		parser: tutorialParser,
+		dir:    tutorialParser.TutorialsDir(),
+		logger: logger,
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	if _, err := l.Reload(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Tutorials returns the current tutorials. The slice and tutorials must not be modified.
+func (l *Library) Tutorials() []*models.Tutorial {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.tutorials
+}
+
+// Find returns a tutorial by ID, or nil if there is none.
+func (l *Library) Find(tutorialID string) *models.Tutorial {
+	return findTutorial(l.Tutorials(), tutorialID)
+}
+
+// Cheatsheets returns the current cheatsheets. The slice and cheatsheets must not be modified.
+func (l *Library) Cheatsheets() []*models.Cheatsheet {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.cheatsheets
+}
+
+// FindCheatsheet returns a cheatsheet by ID, or nil if there is none.
+func (l *Library) FindCheatsheet(cheatsheetID string) *models.Cheatsheet {
+	for _, c := range l.Cheatsheets() {
+		if c.ID == cheatsheetID {
+			return c
+		}
+	}
+	return nil
+}
+
+// LinkCheatsheets returns a copy of a tutorial parsed outside the library, such as one with
+// instructor notes, with its section topics linked to cheatsheet entries.
+func (l *Library) LinkCheatsheets(tutorial *models.Tutorial) *models.Tutorial {
+	l.mu.RLock()
+	linker := l.linker
+	l.mu.RUnlock()
+	return linker.link(tutorial)
+}
+
+// Search returns up to limit hits for a query across the tutorials and cheatsheets, best first.
+func (l *Library) Search(query string, limit int) []search.Hit {
+	l.mu.RLock()
+	index := l.index
+	l.mu.RUnlock()
+	return index.Search(query, limit)
+}
+
+// Version returns a hash of the current content, which changes whenever a tutorial does.
+func (l *Library) Version() string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.version
+}
+
+// LoadErrors returns the tutorials that failed to parse on the last reload, by ID, with
+// their errors. Until they parse again, their previous versions stay loaded.
+func (l *Library) LoadErrors() map[string]string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.loadErrors
+}
+
+// Reload re-parses every tutorial and reports whether the content changed.
+func (l *Library) Reload() (bool, error) {
+	return l.update(true)
+}
+
+// Refresh re-parses the tutorials whose files changed since the last reload and reports
+// whether the content changed.
+func (l *Library) Refresh() (bool, error) {
+	return l.update(false)
+}
+
+// Watch polls the tutorials directory every interval and refreshes changed tutorials
+// until ctx is done.
+func (l *Library) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			changed, err := l.Refresh()
+			if err != nil {
+				l.logger.WarnContext(ctx, "failed to refresh tutorial content", "error", err)
+				continue
+			}
+			if changed {
+				l.logger.InfoContext(ctx, "tutorial content reloaded", "version", l.Version())
+			}
+		}
+	}
+}
+
+// update re-parses all tutorials, or only the changed ones, and swaps in the result.
+// A tutorial that fails to parse keeps its previous version, so a half-saved edit
+// does not take it offline, and its new stamp is not recorded, so the next refresh
+// tries it again. Content whose prerequisites form a cycle is rejected.
+func (l *Library) update(all bool) (bool, error) {
+	l.reloadMu.Lock()
+	defer l.reloadMu.Unlock()
+
+	stamps, err := scanFiles(l.dir)
+	if err != nil {
+		return false, fmt.Errorf("scan tutorials: %w", err)
+	}
+	all = all || stamps[sharedFiles] != l.stamps[sharedFiles]
+
+	cheatsheets, err := l.updateCheatsheets(stamps, all)
+	if err != nil {
+		return false, err
+	}
+
+	ids, err := l.parser.ListTutorials()
+	if err != nil {
+		return false, fmt.Errorf("failed to load tutorials: %w", err)
+	}
+
+	current := make(map[string]*models.Tutorial)
+	for _, t := range l.Tutorials() {
+		current[t.ID] = t
+	}
+
+	tutorials := make([]*models.Tutorial, 0, len(ids))
+	loadErrors := make(map[string]string)
+	for _, id := range ids {
+		previous := current[id]
+		if previous != nil && !all && stamps[id] == l.stamps[id] {
+			tutorials = append(tutorials, previous)
+			continue
+		}
+
+		tutorial, loadErr := l.parser.GetTutorial(id, false)
+		if loadErr != nil {
+			l.logger.Warn("failed to load tutorial", "tutorial", id, "error", loadErr)
+			loadErrors[id] = loadErr.Error()
+			restoreStamp(stamps, l.stamps, id)
+			if previous != nil {
+				tutorials = append(tutorials, previous)
+			}
+			continue
+		}
+		tutorials = append(tutorials, tutorial)
+	}
+
+	// Link every tutorial again, since the cheatsheets may have changed under an unchanged one
+	linker := newCheatsheetLinker(cheatsheets)
+	for i, t := range tutorials {
+		tutorials[i] = linker.link(t)
+	}
+
+	graph, err := newPrerequisiteGraph(tutorials, l.logger)
+	if err != nil {
+		return false, err
+	}
+
+	version, err := contentVersion(tutorials, cheatsheets)
+	if err != nil {
+		return false, err
+	}
+	index := search.NewIndex(tutorials, cheatsheets)
+
+	l.stamps = stamps
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	changed := version != l.version
+	l.tutorials = tutorials
+	l.cheatsheets = cheatsheets
+	l.linker = linker
+	l.graph = graph
+	l.index = index
+	l.version = version
+	l.loadErrors = loadErrors
+	return changed, nil
+}
+
+// restoreStamp puts back the recorded stamp of a tutorial, or drops it if none was recorded.
+func restoreStamp(stamps, recorded map[string]string, id string) {
+	if stamp, ok := recorded[id]; ok {
+		stamps[id] = stamp
+		return
+	}
+	delete(stamps, id)
+}
+
+// updateCheatsheets reloads the cheatsheets if asked to or if they changed, recording their
+// fingerprint in stamps. Otherwise it returns the current ones.
+func (l *Library) updateCheatsheets(stamps map[string]string, all bool) ([]*models.Cheatsheet, error) {
+	if l.cheatsheetsDir == "" {
+		return nil, nil
+	}
+
+	sheetStamps, err := scanFiles(l.cheatsheetsDir)
+	if errors.Is(err, fs.ErrNotExist) {
+		l.logger.Warn("cheatsheets directory not found", "dir", l.cheatsheetsDir)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scan cheatsheets: %w", err)
+	}
+	stamps[cheatsheetFiles] = sheetStamps[sharedFiles]
+
+	l.mu.RLock()
+	current := l.cheatsheets
+	l.mu.RUnlock()
+	if !all && stamps[cheatsheetFiles] == l.stamps[cheatsheetFiles] {
+		return current, nil
+	}
+
+	cheatsheets, err := parser.LoadCheatsheets(l.cheatsheetsDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cheatsheets: %w", err)
+	}
+	return cheatsheets, nil
+}
+
+// scanFiles fingerprints the files of each tutorial by path, size and modification time.
+func scanFiles(dir string) (map[string]string, error) {
+	hashes := make(map[string]hash.Hash)
+
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, walkErr error) error {
+		if walkErr != nil || entry.IsDir() {
+			return walkErr
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		key := tutorialKey(filepath.ToSlash(rel))
+		h, ok := hashes[key]
+		if !ok {
+			h = sha256.New()
+			hashes[key] = h
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00%d\n", rel, info.Size(), info.ModTime().UnixNano())
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stamps := make(map[string]string, len(hashes))
+	for key, h := range hashes {
+		stamps[key] = hex.EncodeToString(h.Sum(nil))
+	}
+	return stamps, nil
+}
+
+// tutorialKey returns the ID of the tutorial a file belongs to, or sharedFiles.
+func tutorialKey(rel string) string {
+	first, _, nested := strings.Cut(rel, "/")
+	if nested {
+		if id, ok := strings.CutPrefix(first, "tutorial-"); ok {
+			return id
+		}
+		return sharedFiles
+	}
+	if strings.HasPrefix(first, "Tutorial-") && strings.HasSuffix(first, ".md") {
+		return parser.ExtractTutorialID(first)
+	}
+	return sharedFiles
+}
+
+// contentVersion hashes the tutorials and cheatsheets into a short version string.
+func contentVersion(tutorials []*models.Tutorial, cheatsheets []*models.Cheatsheet) (string, error) {
+	h := sha256.New()
+	enc := json.NewEncoder(h)
+	for _, t := range tutorials {
+		if err := enc.Encode(t); err != nil {
+			return "", fmt.Errorf("hash tutorial %s: %w", t.ID, err)
+		}
+	}
+	if err := enc.Encode(cheatsheets); err != nil {
+		return "", fmt.Errorf("hash cheatsheets: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:versionLength], nil
+}
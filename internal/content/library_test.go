@@ -0,0 +1,64 @@
+package content_test
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jonesrussell/go-fundamentals-best-practices/internal/content"
+	"github.com/jonesrussell/go-fundamentals-best-practices/internal/parser"
+)
+
+// writeConfig writes the tutorial.yaml of a one-section tutorial under dir.
+func writeConfig(t *testing.T, dir, config string) {
+	t.Helper()
+	tutorialDir := filepath.Join(dir, "tutorial-1")
+	if err := os.MkdirAll(filepath.Join(tutorialDir, "sections"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"tutorial.yaml":        config,
+		"sections/01-intro.md": "# Introduction\n\nText.\n",
+	}
+	for name, text := range files {
+		if err := os.WriteFile(filepath.Join(tutorialDir, name), []byte(text), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// TestRefreshRetriesFailedTutorials checks that a tutorial that fails to parse keeps its
+// previous version, reports its error and is parsed again on the next refresh
+func TestRefreshRetriesFailedTutorials(t *testing.T) {
+	dir := t.TempDir()
+	writeConfig(t, dir, "id: \"1\"\ntitle: \"Basics\"\n")
+
+	library, err := content.NewLibrary(parser.NewTutorialParser(dir), slog.New(slog.DiscardHandler))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeConfig(t, dir, "id: \"1\"\ntitle: [unterminated\n")
+	for range 2 {
+		if _, err := library.Refresh(); err != nil {
+			t.Fatal(err)
+		}
+		if _, failed := library.LoadErrors()["1"]; !failed {
+			t.Fatalf("LoadErrors() = %v, want the broken tutorial", library.LoadErrors())
+		}
+		if title := library.Find("1").Title; title != "Basics" {
+			t.Errorf("title = %q, want the previous version kept", title)
+		}
+	}
+
+	writeConfig(t, dir, "id: \"1\"\ntitle: \"Go Basics\"\n")
+	changed, err := library.Refresh()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !changed || len(library.LoadErrors()) != 0 || library.Find("1").Title != "Go Basics" {
+		t.Errorf("changed = %v, errors = %v, title = %q, want the fixed tutorial loaded",
+			changed, library.LoadErrors(), library.Find("1").Title)
+	}
+}
@@ -0,0 +1,151 @@
+package content
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/jonesrussell/go-fundamentals-best-practices/pkg/models"
+)
+
+// ErrPrerequisiteCycle is returned when tutorials require each other, directly or through others.
+var ErrPrerequisiteCycle = errors.New("prerequisite cycle")
+
+// prerequisiteGraph is the directed acyclic graph of tutorials and the tutorials they require.
+type prerequisiteGraph struct {
+	requires map[string][]string // Tutorial ID -> IDs of the loaded tutorials it requires
+	order    []string            // Tutorial IDs, each after the tutorials it requires
+}
+
+// newPrerequisiteGraph builds the prerequisite graph of the tutorials. References to tutorials
+// that are not loaded are logged and left out; a cycle is an error.
+func newPrerequisiteGraph(tutorials []*models.Tutorial, logger *slog.Logger) (*prerequisiteGraph, error) {
+	g := &prerequisiteGraph{requires: make(map[string][]string, len(tutorials))}
+
+	position := make(map[string]int, len(tutorials))
+	for i, t := range tutorials {
+		position[t.ID] = i
+	}
+	for _, t := range tutorials {
+		for _, id := range t.RequiredIDs {
+			if _, ok := position[id]; !ok {
+				logger.Warn("tutorial requires unknown tutorial", "tutorial", t.ID, "required", id)
+				continue
+			}
+			if !slices.Contains(g.requires[t.ID], id) {
+				g.requires[t.ID] = append(g.requires[t.ID], id)
+			}
+		}
+	}
+
+	// Kahn's algorithm, taking the earliest ready tutorial each time so the order
+	// follows the course order wherever prerequisites allow
+	waiting := make(map[string]int, len(tutorials)) // Tutorial ID -> prerequisites not yet ordered
+	for _, t := range tutorials {
+		waiting[t.ID] = len(g.requires[t.ID])
+	}
+	done := make(map[string]bool, len(tutorials))
+	for len(g.order) < len(tutorials) {
+		next := ""
+		for _, t := range tutorials {
+			if !done[t.ID] && waiting[t.ID] == 0 {
+				next = t.ID
+				break
+			}
+		}
+		if next == "" {
+			return nil, fmt.Errorf("%w: %s", ErrPrerequisiteCycle, g.findCycle(done))
+		}
+
+		done[next] = true
+		g.order = append(g.order, next)
+		for _, t := range tutorials {
+			if slices.Contains(g.requires[t.ID], next) {
+				waiting[t.ID]--
+			}
+		}
+	}
+
+	return g, nil
+}
+
+// findCycle returns a cycle among the tutorials not yet ordered, e.g. "2 -> 5 -> 2".
+// Every such tutorial requires another one, so following requirements must loop.
+func (g *prerequisiteGraph) findCycle(done map[string]bool) string {
+	var start string
+	for id := range g.requires {
+		if !done[id] && (start == "" || id < start) {
+			start = id
+		}
+	}
+
+	var path []string
+	seen := make(map[string]int)
+	for id := start; ; {
+		if i, ok := seen[id]; ok {
+			return strings.Join(append(path[i:], id), " -> ")
+		}
+		seen[id] = len(path)
+		path = append(path, id)
+		for _, required := range g.requires[id] {
+			if !done[required] {
+				id = required
+				break
+			}
+		}
+	}
+}
+
+// ancestors returns every tutorial a tutorial requires, directly or through others, in learning order.
+func (g *prerequisiteGraph) ancestors(tutorialID string) []string {
+	needed := map[string]bool{}
+	var visit func(id string)
+	visit = func(id string) {
+		for _, required := range g.requires[id] {
+			if !needed[required] {
+				needed[required] = true
+				visit(required)
+			}
+		}
+	}
+	visit(tutorialID)
+
+	ids := make([]string, 0, len(needed))
+	for _, id := range g.order {
+		if needed[id] {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// Prerequisites returns the tutorials a tutorial requires, directly or through others,
+// in the order to take them.
+func (l *Library) Prerequisites(tutorialID string) []*models.Tutorial {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if l.graph == nil {
+		return nil
+	}
+	ids := l.graph.ancestors(tutorialID)
+	required := make([]*models.Tutorial, 0, len(ids))
+	for _, id := range ids {
+		if t := findTutorial(l.tutorials, id); t != nil {
+			required = append(required, t)
+		}
+	}
+	return required
+}
+
+// findTutorial returns the tutorial with an ID, or nil.
+func findTutorial(tutorials []*models.Tutorial, tutorialID string) *models.Tutorial {
+	for _, t := range tutorials {
+		if t.ID == tutorialID {
+			return t
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package content_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jonesrussell/go-fundamentals-best-practices/internal/content"
+	"github.com/jonesrussell/go-fundamentals-best-practices/pkg/models"
+)
+
+// tutorial returns a one-section tutorial that requires the tutorials with the given IDs.
+func tutorial(id string, required ...string) *models.Tutorial {
+	return &models.Tutorial{
+		ID:          id,
+		RequiredIDs: required,
+		Sections:    []models.Section{{ID: "intro"}},
+	}
+}
+
+func TestPrerequisiteGraph(t *testing.T) {
+	tests := []struct {
+		name      string
+		tutorials []*models.Tutorial
+		completed []string // Tutorials the user has finished
+		wantCycle string   // Cycle in the error, when the graph has one
+		wantAll   []string // Every tutorial the last tutorial requires, in order
+		wantNext  []string // Recommended tutorials as "ID:reason", best first
+	}{
+		{
+			name:      "satisfied chain",
+			tutorials: []*models.Tutorial{tutorial("1"), tutorial("2", "1"), tutorial("3", "2")},
+			completed: []string{"1"},
+			wantAll:   []string{"1", "2"},
+			wantNext:  []string{"2:" + content.ReasonPrerequisitesComplete},
+		},
+		{
+			name:      "unknown ID",
+			tutorials: []*models.Tutorial{tutorial("1"), tutorial("2", "1", "99")},
+			wantAll:   []string{"1"},
+			wantNext:  []string{"1:" + content.ReasonNoPrerequisites},
+		},
+		{
+			name:      "cycle",
+			tutorials: []*models.Tutorial{tutorial("1"), tutorial("2", "1", "3"), tutorial("3", "2")},
+			wantCycle: "2 -> 3 -> 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			library, err := content.NewTestLibrary(tt.tutorials)
+			if tt.wantCycle != "" {
+				if !errors.Is(err, content.ErrPrerequisiteCycle) || !strings.Contains(err.Error(), tt.wantCycle) {
+					t.Fatalf("error = %v, want a cycle %s", err, tt.wantCycle)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			last := tt.tutorials[len(tt.tutorials)-1].ID
+			var all []string
+			for _, required := range library.Prerequisites(last) {
+				all = append(all, required.ID)
+			}
+			if strings.Join(all, ",") != strings.Join(tt.wantAll, ",") {
+				t.Errorf("prerequisites of %s = %v, want %v", last, all, tt.wantAll)
+			}
+
+			progress := &models.Progress{CompletedSections: map[string][]string{}}
+			for _, id := range tt.completed {
+				progress.CompletedSections[id] = []string{"intro"}
+			}
+			var next []string
+			for _, r := range library.Recommend(progress) {
+				next = append(next, r.Tutorial.ID+":"+r.Reason)
+			}
+			if strings.Join(next, ",") != strings.Join(tt.wantNext, ",") {
+				t.Errorf("recommendations = %v, want %v", next, tt.wantNext)
+			}
+		})
+	}
+}
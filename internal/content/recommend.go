@@ -0,0 +1,106 @@
+package content
+
+import (
+	"slices"
+
+	"github.com/jonesrussell/go-fundamentals-best-practices/pkg/models"
+)
+
+// Reasons a tutorial is recommended.
+const (
+	// ReasonInProgress is a tutorial the user has started but not finished.
+	ReasonInProgress = "in-progress"
+	// ReasonPrerequisitesComplete is a tutorial whose required tutorials the user has finished.
+	ReasonPrerequisitesComplete = "prerequisites-complete"
+	// ReasonNoPrerequisites is a tutorial that requires no other tutorial.
+	ReasonNoPrerequisites = "no-prerequisites"
+)
+
+// Recommendation is a tutorial the user could take next.
+type Recommendation struct {
+	Tutorial          *models.Tutorial
+	Reason            string
+	CompletedSections int
+}
+
+// Recommend returns the tutorials a user can take next, best first: the tutorial they are
+// in the middle of, other started tutorials, then unstarted tutorials whose required tutorials
+// they have finished, in learning order. Finished tutorials are left out.
+func (l *Library) Recommend(progress *models.Progress) []Recommendation {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if l.graph == nil {
+		return nil
+	}
+
+	finished := func(t *models.Tutorial) bool {
+		return len(t.Sections) > 0 && completedSections(t, progress) == len(t.Sections)
+	}
+
+	var recommendations []Recommendation
+	for _, id := range l.graph.order {
+		t := findTutorial(l.tutorials, id)
+		if t == nil || finished(t) {
+			continue
+		}
+
+		ready := true
+		for _, required := range l.graph.requires[id] {
+			if rt := findTutorial(l.tutorials, required); rt != nil && !finished(rt) {
+				ready = false
+				break
+			}
+		}
+
+		r := Recommendation{Tutorial: t, CompletedSections: completedSections(t, progress)}
+		switch {
+		case r.CompletedSections > 0:
+			r.Reason = ReasonInProgress
+		case !ready:
+			continue
+		case len(l.graph.requires[id]) == 0:
+			r.Reason = ReasonNoPrerequisites
+		default:
+			r.Reason = ReasonPrerequisitesComplete
+		}
+		recommendations = append(recommendations, r)
+	}
+
+	slices.SortStableFunc(recommendations, func(a, b Recommendation) int {
+		return recommendationRank(a, progress) - recommendationRank(b, progress)
+	})
+	return recommendations
+}
+
+// Recommendation ranks, best first.
+const (
+	rankCurrent = iota
+	rankStarted
+	rankReady
+)
+
+// recommendationRank orders recommendations: the current tutorial, then started ones, then the rest.
+func recommendationRank(r Recommendation, progress *models.Progress) int {
+	switch {
+	case r.Reason == ReasonInProgress && r.Tutorial.ID == progress.CurrentTutorial:
+		return rankCurrent
+	case r.Reason == ReasonInProgress:
+		return rankStarted
+	default:
+		return rankReady
+	}
+}
+
+// completedSections counts the tutorial's sections the user has completed, ignoring
+// completed IDs of sections the tutorial no longer has.
+func completedSections(t *models.Tutorial, progress *models.Progress) int {
+	completed := progress.CompletedSections[t.ID]
+	count := 0
+	for _, section := range t.Sections {
+		if slices.Contains(completed, section.ID) {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,138 @@
+package executor
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Programs can write files to an output directory that is returned as artifacts.
+const (
+	// artifactsDir is the writable tmpfs directory programs write artifacts to.
+	artifactsDir = "/out"
+	// artifactsArchive is where the run command archives artifactsDir before the tmpfs goes away.
+	artifactsArchive = "/artifacts.tar"
+	// maxArtifactsBytes caps the size of the artifacts tmpfs.
+	maxArtifactsBytes = 8 * bytesPerKB * bytesPerKB
+	// maxArtifactsArchiveBytes leaves room for archive headers and padding around the files.
+	maxArtifactsArchiveBytes = 2 * maxArtifactsBytes
+	// maxInlineArtifactBytes is the largest artifact returned inline.
+	maxInlineArtifactBytes = bytesPerKB * bytesPerKB
+	// maxArtifacts bounds the number of artifacts returned. The rest are counted in the result.
+	maxArtifacts = 20
+	// sniffBytes is how much content MIME type sniffing looks at.
+	sniffBytes = 512
+)
+
+// artifactTypes covers common output formats missing from minimal systems' MIME tables.
+var artifactTypes = map[string]string{
+	".csv": "text/csv; charset=utf-8",
+	".md":  "text/markdown; charset=utf-8",
+	".txt": "text/plain; charset=utf-8",
+}
+
+// runCommand runs the binary, then archives the artifacts directory, keeping the program's exit code.
+func runCommand(opts RunOptions) string {
+	return fmt.Sprintf(
+		"/binary%s; status=$?; tar -C %s -cf %s . 2>/dev/null; exit $status",
+		testArgs(opts), artifactsDir, artifactsArchive,
+	)
+}
+
+// Artifact is a file the program wrote to /out.
+type Artifact struct {
+	Name     string `json:"name"` // Path relative to /out
+	Size     int64  `json:"size"`
+	MIMEType string `json:"mimeType"`
+	Data     string `json:"data,omitempty"`    // Base64 contents, for files up to 1MB
+	Omitted  bool   `json:"omitted,omitempty"` // Too large to return inline
+	// InlineLimit is the largest size returned inline, set on omitted artifacts.
+	InlineLimit int64 `json:"inlineLimit,omitempty"`
+}
+
+// artifactsTmpfs returns the tmpfs mount options for the artifacts directory.
+func artifactsTmpfs() map[string]string {
+	return map[string]string{
+		artifactsDir: fmt.Sprintf("rw,size=%d,mode=1777", maxArtifactsBytes),
+	}
+}
+
+// parseArtifacts reads the archive of the artifacts directory. It returns the first maxArtifacts
+// files and the number of files left out.
+func parseArtifacts(archive []byte) ([]Artifact, int, error) {
+	artifacts := []Artifact{}
+	truncated := 0
+	tr := tar.NewReader(bytes.NewReader(archive))
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("read artifacts archive: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if len(artifacts) == maxArtifacts {
+			truncated++
+			continue
+		}
+
+		artifact, err := newArtifact(header, tr)
+		if err != nil {
+			return nil, 0, err
+		}
+		artifacts = append(artifacts, artifact)
+	}
+
+	sort.Slice(artifacts, func(i, j int) bool { return artifacts[i].Name < artifacts[j].Name })
+	return artifacts, truncated, nil
+}
+
+// newArtifact describes one archived file, inlining its contents when it is small enough.
+func newArtifact(header *tar.Header, r io.Reader) (Artifact, error) {
+	artifact := Artifact{
+		Name: strings.TrimPrefix(path.Clean(header.Name), "./"),
+		Size: header.Size,
+	}
+
+	// Sniff from the start of files too large to inline
+	sniffLen := int64(sniffBytes)
+	if header.Size <= maxInlineArtifactBytes {
+		sniffLen = header.Size
+	}
+	content, err := io.ReadAll(io.LimitReader(r, sniffLen))
+	if err != nil {
+		return Artifact{}, fmt.Errorf("read artifact %s: %w", artifact.Name, err)
+	}
+	artifact.MIMEType = detectMIMEType(artifact.Name, content)
+
+	if header.Size > maxInlineArtifactBytes {
+		artifact.Omitted = true
+		artifact.InlineLimit = maxInlineArtifactBytes
+		return artifact, nil
+	}
+	artifact.Data = base64.StdEncoding.EncodeToString(content)
+	return artifact, nil
+}
+
+// detectMIMEType prefers the type registered for the file extension and falls back to sniffing
+// the contents, which cannot tell formats like SVG or CSV from plain text.
+func detectMIMEType(name string, content []byte) string {
+	if known, ok := artifactTypes[strings.ToLower(path.Ext(name))]; ok {
+		return known
+	}
+	if byExt := mime.TypeByExtension(path.Ext(name)); byExt != "" {
+		return byExt
+	}
+	return http.DetectContentType(content)
+}
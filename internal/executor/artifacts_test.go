@@ -0,0 +1,55 @@
+package executor_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/jonesrussell/go-fundamentals-best-practices/internal/executor"
+)
+
+// writeArchive builds a tar archive of the given files, in order.
+func writeArchive(t *testing.T, files []string, sizes map[string]int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, name := range files {
+		data := bytes.Repeat([]byte("x"), sizes[name])
+		if err := tw.WriteHeader(&tar.Header{Name: "./" + name, Mode: 0o644, Size: int64(len(data))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestParseArtifactsTruncated(t *testing.T) {
+	var files []string
+	for i := range 23 {
+		files = append(files, fmt.Sprintf("file-%02d.txt", i))
+	}
+	sizes := map[string]int{"file-00.txt": 2 << 20, "file-01.txt": 3}
+
+	artifacts, truncated, err := executor.ParseArtifacts(writeArchive(t, files, sizes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(artifacts) != 20 || truncated != 3 {
+		t.Fatalf("got %d artifacts and %d truncated, want 20 and 3", len(artifacts), truncated)
+	}
+
+	large := artifacts[0]
+	if !large.Omitted || large.Data != "" || large.InlineLimit != 1<<20 || large.Size != 2<<20 {
+		t.Errorf("large artifact = %+v, want it omitted with the inline limit", large)
+	}
+	small := artifacts[1]
+	if small.Omitted || small.Data != "eHh4" || small.InlineLimit != 0 {
+		t.Errorf("small artifact = %+v, want its contents inline", small)
+	}
+}
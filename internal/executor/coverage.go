@@ -0,0 +1,191 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Coverage builds use Go's coverage instrumentation (-cover). Programs write raw counters to
+// GOCOVERDIR on exit, which go tool covdata converts to a text profile in the compile image;
+// test binaries write the text profile themselves.
+const (
+	// coverMode counts how often each block ran rather than just whether it did.
+	coverMode = "count"
+	// coverContainerDir is GOCOVERDIR inside the run container, and where test binaries write their profile.
+	coverContainerDir = "/coverage"
+	// coverProfileFile is the text profile, written by a test binary or converted from a program's counters.
+	coverProfileFile = "cover.out"
+	// coverDataDir holds a program's counters in the workspace while they are converted.
+	coverDataDir = "covdata"
+	// maxCoverageBytes bounds the coverage data copied out of the container.
+	maxCoverageBytes = 4 * bytesPerKB * bytesPerKB
+	// coverPercentScale rounds the coverage percentage to one decimal place.
+	coverPercentScale = 10
+	// percentMultiplier converts a fraction to a percentage.
+	percentMultiplier = 100
+)
+
+// coverConvertCommand converts the counters in coverDataDir to a text profile.
+var coverConvertCommand = fmt.Sprintf("go tool covdata textfmt -i=%s -o=%s", coverDataDir, coverProfileFile)
+
+// errCoverageFormat is returned for a coverage profile that cannot be read.
+var errCoverageFormat = errors.New("malformed coverage profile")
+
+// Coverage reports which statements of the submitted program ran, and how often.
+type Coverage struct {
+	Mode       string          `json:"mode"`
+	Statements int             `json:"statements"`
+	Covered    int             `json:"covered"`
+	Percent    float64         `json:"percent"`
+	Lines      []LineCoverage  `json:"lines"`
+	Blocks     []CoverageBlock `json:"blocks"`
+}
+
+// LineCoverage is the hit count of a source line: the highest count of the blocks on it.
+// Partial is set when some statements on the line ran and others did not.
+type LineCoverage struct {
+	Line    int  `json:"line"`
+	Count   int  `json:"count"`
+	Partial bool `json:"partial,omitempty"`
+}
+
+// CoverageBlock is a run of statements that always execute together.
+type CoverageBlock struct {
+	StartLine  int `json:"startLine"`
+	StartCol   int `json:"startCol"`
+	EndLine    int `json:"endLine"`
+	EndCol     int `json:"endCol"`
+	Statements int `json:"statements"`
+	Count      int `json:"count"`
+}
+
+// parseCoverProfile reads a text coverage profile, as written by go test -coverprofile and
+// go tool covdata textfmt, into block and line coverage for code.go.
+//
+// Each line after the mode gives a block of a file as
+// "file:startLine.startCol,endLine.endCol statements count". Blocks listed more than once,
+// as when several runs are merged, have their counts added.
+func parseCoverProfile(data []byte) (*Coverage, error) {
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	mode, ok := strings.CutPrefix(lines[0], "mode: ")
+	if !ok {
+		return nil, fmt.Errorf("%w: missing mode line", errCoverageFormat)
+	}
+
+	coverage := &Coverage{Mode: mode, Blocks: []CoverageBlock{}}
+	index := map[CoverageBlock]int{} // Block without its count -> position in Blocks
+	for _, line := range lines[1:] {
+		file, block, err := parseCoverProfileLine(line)
+		if err != nil {
+			return nil, err
+		}
+		if path.Base(file) != "code.go" {
+			continue
+		}
+
+		count := block.Count
+		block.Count = 0
+		if i, seen := index[block]; seen {
+			coverage.Blocks[i].Count += count
+			continue
+		}
+		index[block] = len(coverage.Blocks)
+		block.Count = count
+		coverage.Blocks = append(coverage.Blocks, block)
+	}
+
+	sort.Slice(coverage.Blocks, func(i, j int) bool {
+		a, b := coverage.Blocks[i], coverage.Blocks[j]
+		if a.StartLine != b.StartLine {
+			return a.StartLine < b.StartLine
+		}
+		return a.StartCol < b.StartCol
+	})
+
+	coverage.summarize()
+	return coverage, nil
+}
+
+// parseCoverProfileLine reads the file and block of a profile line.
+func parseCoverProfileLine(line string) (string, CoverageBlock, error) {
+	var block CoverageBlock
+	// File names may contain colons, so the block starts after the last one
+	sep := strings.LastIndexByte(line, ':')
+	if sep < 0 {
+		return "", block, fmt.Errorf("%w: %q", errCoverageFormat, line)
+	}
+	_, err := fmt.Sscanf(line[sep+1:], "%d.%d,%d.%d %d %d",
+		&block.StartLine, &block.StartCol, &block.EndLine, &block.EndCol, &block.Statements, &block.Count)
+	if err != nil {
+		return "", block, fmt.Errorf("%w: %q: %w", errCoverageFormat, line, err)
+	}
+	return line[:sep], block, nil
+}
+
+// summarize computes the statement totals and per-line counts from the blocks.
+func (c *Coverage) summarize() {
+	c.Statements, c.Covered = 0, 0
+	lines := map[int]*LineCoverage{}
+	for _, block := range c.Blocks {
+		c.Statements += block.Statements
+		if block.Count > 0 {
+			c.Covered += block.Statements
+		}
+		if block.Statements == 0 {
+			continue
+		}
+
+		// End columns are exclusive, so a block ending at column 1 stops on the line before
+		endLine := block.EndLine
+		if block.EndCol <= 1 && endLine > block.StartLine {
+			endLine--
+		}
+		for line := block.StartLine; line <= endLine; line++ {
+			lc, ok := lines[line]
+			if !ok {
+				lines[line] = &LineCoverage{Line: line, Count: block.Count}
+				continue
+			}
+			if (lc.Count > 0) != (block.Count > 0) {
+				lc.Partial = true
+			}
+			lc.Count = max(lc.Count, block.Count)
+		}
+	}
+
+	c.Lines = make([]LineCoverage, 0, len(lines))
+	for _, lc := range lines {
+		c.Lines = append(c.Lines, *lc)
+	}
+	sort.Slice(c.Lines, func(i, j int) bool { return c.Lines[i].Line < c.Lines[j].Line })
+
+	c.Percent = 0
+	if c.Statements > 0 {
+		c.Percent = math.Round(float64(c.Covered)*percentMultiplier*coverPercentScale/float64(c.Statements)) / coverPercentScale
+	}
+}
+
+// mapToSource rewrites block positions from the prepared program to the submitted code.
+// Blocks that start in generated wrapper code are dropped; blocks that end in it are
+// clipped to the last line of the submitted code.
+func (c *Coverage) mapToSource(sm sourceMap) {
+	blocks := c.Blocks[:0]
+	for _, block := range c.Blocks {
+		startLine, startCol, ok := sm.toUser(block.StartLine, block.StartCol)
+		if !ok {
+			continue
+		}
+		endLine, endCol, endOK := sm.toUser(block.EndLine, block.EndCol)
+		if !endOK {
+			endLine, endCol = sm.userLines, 0
+		}
+		block.StartLine, block.StartCol, block.EndLine, block.EndCol = startLine, startCol, endLine, endCol
+		blocks = append(blocks, block)
+	}
+	c.Blocks = blocks
+	c.summarize()
+}
@@ -0,0 +1,84 @@
+package executor_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jonesrussell/go-fundamentals-best-practices/internal/executor"
+)
+
+// gradeProfile is the go tool covdata textfmt output of a program whose switch took its first case.
+// The last block is listed again by a second run, and a block of another package is skipped.
+const gradeProfile = `mode: count
+/workspace/code.go:6.2,6.9 1 1
+/workspace/code.go:8.3,8.13 1 1
+/workspace/code.go:10.3,10.13 1 0
+/workspace/code.go:15.2,16.1 1 1
+example.com/lib/lib.go:3.20,5.2 2 4
+/workspace/code.go:15.2,16.1 1 1
+`
+
+// gradeTestProfile is the go test -coverprofile output of the same code, tested for its default case.
+const gradeTestProfile = `mode: count
+/workspace/code.go:6.2,6.9 1 1
+/workspace/code.go:8.3,8.13 1 0
+/workspace/code.go:10.3,10.13 1 1
+/workspace/code.go:15.2,16.1 1 0
+`
+
+func TestParseCoverProfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile string
+		want    *executor.Coverage
+	}{
+		{
+			name:    "program",
+			profile: gradeProfile,
+			want: &executor.Coverage{
+				Mode: "count", Statements: 4, Covered: 3, Percent: 75,
+				Lines: []executor.LineCoverage{{Line: 6, Count: 1}, {Line: 8, Count: 1}, {Line: 10}, {Line: 15, Count: 2}},
+				Blocks: []executor.CoverageBlock{
+					{StartLine: 6, StartCol: 2, EndLine: 6, EndCol: 9, Statements: 1, Count: 1},
+					{StartLine: 8, StartCol: 3, EndLine: 8, EndCol: 13, Statements: 1, Count: 1},
+					{StartLine: 10, StartCol: 3, EndLine: 10, EndCol: 13, Statements: 1},
+					{StartLine: 15, StartCol: 2, EndLine: 16, EndCol: 1, Statements: 1, Count: 2},
+				},
+			},
+		},
+		{
+			name:    "tests",
+			profile: gradeTestProfile,
+			want: &executor.Coverage{
+				Mode: "count", Statements: 4, Covered: 2, Percent: 50,
+				Lines: []executor.LineCoverage{{Line: 6, Count: 1}, {Line: 8}, {Line: 10, Count: 1}, {Line: 15}},
+				Blocks: []executor.CoverageBlock{
+					{StartLine: 6, StartCol: 2, EndLine: 6, EndCol: 9, Statements: 1, Count: 1},
+					{StartLine: 8, StartCol: 3, EndLine: 8, EndCol: 13, Statements: 1},
+					{StartLine: 10, StartCol: 3, EndLine: 10, EndCol: 13, Statements: 1, Count: 1},
+					{StartLine: 15, StartCol: 2, EndLine: 16, EndCol: 1, Statements: 1},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := executor.ParseCoverProfile([]byte(tt.profile))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("coverage = %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCoverProfileMalformed(t *testing.T) {
+	for _, profile := range []string{"", "/workspace/code.go:6.2,6.9 1 1\n", "mode: count\n/workspace/code.go:6.2 1 1\n"} {
+		if _, err := executor.ParseCoverProfile([]byte(profile)); err == nil {
+			t.Errorf("ParseCoverProfile(%q) succeeded, want an error", profile)
+		}
+	}
+}
@@ -4,11 +4,14 @@ import (
 	"archive/tar"
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"os"
+	"path"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/containerd/errdefs"
@@ -29,6 +32,8 @@ const (
 	containerWorkspace = "/workspace"
 	// Docker connection timeout
 	dockerConnectionTimeout = 5 * time.Second
+	// imagePullTimeout bounds pulling the images at startup, which can take minutes on a cold host
+	imagePullTimeout = 10 * time.Minute
 	// CPU period in microseconds (100ms)
 	cpuPeriodMicroseconds = 100000
 	// CPU percentage denominator
@@ -37,24 +42,25 @@ const (
 	bytesPerKB = 1024
 	// Binary file permissions
 	binaryFileMode = 0o755
+	// Permissions for directories created in run containers
+	dirFileMode = 0o777
 )
 
 // dockerExecutor handles execution of Go code using Docker containers.
 type dockerExecutor struct {
-	client        *client.Client
-	compileImage  string
-	execImage     string
-	maxMemoryMB   int
-	maxCPUPercent int
-	maxOutput     int
-	timeout       time.Duration
-	logger        *slog.Logger
+	client    *client.Client
+	execImage string
+	maxOutput int
+	timeout   time.Duration
+	logger    *slog.Logger
 }
 
-// newDockerExecutor creates a new Docker-based executor.
+// newDockerExecutor creates a new Docker-based executor. The compile image of every toolchain
+// is pulled up front, so the first run with a toolchain doesn't spend its timeout pulling it.
 func newDockerExecutor(
-	compileImage, execImage string,
-	maxMemoryMB, maxCPUPercent, maxOutput int,
+	compileImages []string,
+	execImage string,
+	maxOutput int,
 	timeout time.Duration,
 	logger *slog.Logger,
 ) (*dockerExecutor, error) {
@@ -73,22 +79,24 @@ func newDockerExecutor(
 	}
 
 	executor := &dockerExecutor{
-		client:        cli,
-		compileImage:  compileImage,
-		execImage:     execImage,
-		maxMemoryMB:   maxMemoryMB,
-		maxCPUPercent: maxCPUPercent,
-		maxOutput:     maxOutput,
-		timeout:       timeout,
-		logger:        logger,
+		client:    cli,
+		execImage: execImage,
+		maxOutput: maxOutput,
+		timeout:   timeout,
+		logger:    logger,
 	}
 
 	// Ensure required images are available (pull if needed)
-	if pullErr := executor.ensureImage(pingCtx, compileImage); pullErr != nil {
-		return nil, fmt.Errorf("ensure compile image %s: %w", compileImage, pullErr)
+	pullCtx, pullCancel := context.WithTimeout(context.Background(), imagePullTimeout)
+	defer pullCancel()
+
+	for _, compileImage := range compileImages {
+		if pullErr := executor.ensureImage(pullCtx, compileImage); pullErr != nil {
+			return nil, fmt.Errorf("ensure compile image %s: %w", compileImage, pullErr)
+		}
 	}
 
-	if pullErr := executor.ensureImage(pingCtx, execImage); pullErr != nil {
+	if pullErr := executor.ensureImage(pullCtx, execImage); pullErr != nil {
 		return nil, fmt.Errorf("ensure exec image %s: %w", execImage, pullErr)
 	}
 
@@ -131,7 +139,7 @@ func (de *dockerExecutor) ensureImage(ctx context.Context, imageName string) err
 }
 
 // execute runs Go code in a Docker container using two-stage execution.
-func (de *dockerExecutor) execute(ctx context.Context, code string) (*ExecutionResult, error) {
+func (de *dockerExecutor) execute(ctx context.Context, compileImage, code string, opts RunOptions) (*ExecutionResult, error) {
 	startTime := time.Now()
 
 	// Create a temporary directory for compilation artifacts
@@ -145,49 +153,179 @@ func (de *dockerExecutor) execute(ctx context.Context, code string) (*ExecutionR
 		}
 	}()
 
-	// Write code to temporary file
-	codeFile := filepath.Join(tempDir, "code.go")
-	if writeErr := os.WriteFile(codeFile, []byte(code), 0o600); writeErr != nil {
-		return nil, fmt.Errorf("write code file: %w", writeErr)
+	sources := map[string]string{"code.go": code}
+	if opts.Tests != "" {
+		sources[testFile] = opts.Tests
+	}
+
+	// Visualization builds record variables after each statement
+	if opts.Visualize {
+		instrumented, ok := instrumentVisualization(sources["code.go"])
+		opts.Visualize = ok
+		if ok {
+			// The recorder reads every variable in scope, which would hide "declared and not
+			// used" errors, so the code as written is compiled on its own first
+			sources[filepath.Join(vizPlainDir, "code.go")] = sources["code.go"]
+			sources["code.go"] = instrumented
+			sources[vizRecorderFile] = vizRecorderSource
+		}
+	}
+
+	// Trace builds wrap the user's main in a harness that records a runtime trace
+	if opts.Trace {
+		instrumented, ok := instrumentTrace(sources["code.go"])
+		opts.Trace = ok
+		if ok {
+			sources["code.go"] = instrumented
+			sources[traceMainFile] = traceMainSource
+		}
+	}
+
+	// Write source files to the temporary directory
+	for name, source := range sources {
+		if mkdirErr := os.MkdirAll(filepath.Join(tempDir, filepath.Dir(name)), 0o700); mkdirErr != nil {
+			return nil, fmt.Errorf("create source directory: %w", mkdirErr)
+		}
+		if writeErr := os.WriteFile(filepath.Join(tempDir, name), []byte(source), 0o600); writeErr != nil {
+			return nil, fmt.Errorf("write code file: %w", writeErr)
+		}
+	}
+
+	// Input files live outside the workspace so the run container only sees them
+	var inputDir string
+	if len(opts.Files) > 0 {
+		inputDir = filepath.Join(tempDir, "fixtures")
+		if writeErr := writeInputFiles(inputDir, opts.Files); writeErr != nil {
+			return nil, writeErr
+		}
 	}
 
 	// Stage 1: Compile the code
-	binaryPath, err := de.compileCode(ctx, tempDir)
+	binaryPath, buildOutput, err := de.compileCode(ctx, compileImage, tempDir, opts)
 	if err != nil {
+		var rejected *compileError
 		return &ExecutionResult{
-			Output:   "",
-			Error:    err.Error(),
-			ExitCode: -1,
-			Duration: time.Since(startTime).String(),
+			Output:        "",
+			Error:         err.Error(),
+			ExitCode:      -1,
+			Duration:      time.Since(startTime).String(),
+			compileFailed: errors.As(err, &rejected),
 		}, nil
 	}
 
 	// Stage 2: Execute the compiled binary
-	result, execErr := de.executeBinary(ctx, binaryPath)
+	result, execErr := de.executeBinary(ctx, binaryPath, inputDir, opts)
 	if execErr != nil {
 		return nil, execErr
 	}
 
+	if opts.insight() {
+		result.Insight = parseInsight(buildOutput, opts.Assembly)
+	}
+	if opts.Coverage {
+		result.Coverage = de.convertCoverage(ctx, compileImage, tempDir, result.coverData)
+		result.coverData = nil
+	}
+
 	result.Duration = time.Since(startTime).String()
 	return result, nil
 }
 
 // compileCode compiles Go code to a binary using a Docker container.
-func (de *dockerExecutor) compileCode(ctx context.Context, tempDir string) (string, error) {
-	// Use parent context directly (timeout already applied)
-	compileCtx := ctx
+// It returns the binary path and the compiler output, which carries diagnostics for insight builds.
+func (de *dockerExecutor) compileCode(
+	ctx context.Context,
+	compileImage, tempDir string,
+	opts RunOptions,
+) (binaryPath, output string, err error) {
+	command := buildCommand(opts)
+
+	output, statusCode, err := de.runInCompileContainer(ctx, compileImage, tempDir, command)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %w", ErrCompilationFailed, err)
+	}
+	if statusCode != 0 {
+		// Return compiler output so users can see compilation errors
+		if output == "" {
+			output = fmt.Sprintf("exit code %d", statusCode)
+		}
+		return "", "", &compileError{output: output}
+	}
+
+	// Binary should now exist in tempDir
+	binaryPath = filepath.Join(tempDir, "binary")
+	if _, statErr := os.Stat(binaryPath); statErr != nil {
+		return "", "", fmt.Errorf("%w: binary not found after compilation", ErrCompilationFailed)
+	}
+
+	return binaryPath, output, nil
+}
+
+// compileError is returned when the compiler rejects the code, as opposed to the build failing to run.
+type compileError struct {
+	output string
+}
+
+// Error returns the compiler output, like other compilation failures.
+func (e *compileError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrCompilationFailed, e.output)
+}
+
+// Unwrap lets callers match the error with ErrCompilationFailed.
+func (e *compileError) Unwrap() error {
+	return ErrCompilationFailed
+}
+
+// buildCommand returns the go build command line for the given options.
+// Visualization builds first compile the code as written, so its compile errors are reported as they would be without the recorder.
+func buildCommand(opts RunOptions) string {
+	args := []string{"go", "build"}
+	if opts.Tests != "" {
+		args = []string{"go", "test", "-c"}
+	}
+	if opts.Deterministic {
+		args = append(args, "-tags="+faketimeBuildTag)
+	}
 
-	// Ensure image is available (should already be pulled at init, but double-check on error)
-	resp, createErr := de.createContainerWithImageCheck(compileCtx, de.compileImage, func() (*container.Config, *container.HostConfig) {
+	var check string
+	if opts.Visualize {
+		// Built from its own directory so diagnostics name ./code.go as in a normal build
+		check = "(cd " + vizPlainDir + " && " + strings.Join(args, " ") + " -o /dev/null code.go) && "
+	}
+	if opts.insight() {
+		args = append(args, "-gcflags='"+buildGCFlags(opts)+"'")
+	}
+	if opts.Coverage {
+		args = append(args, "-cover", "-covermode="+coverMode)
+	}
+	args = append(args,
+		"-o", filepath.Join(containerWorkspace, "binary"),
+		filepath.Join(containerWorkspace, "code.go"),
+	)
+	if opts.Tests != "" {
+		args = append(args, filepath.Join(containerWorkspace, testFile))
+	}
+	if opts.Visualize {
+		args = append(args, filepath.Join(containerWorkspace, vizRecorderFile))
+	}
+	if opts.Trace {
+		args = append(args, filepath.Join(containerWorkspace, traceMainFile))
+	}
+	return check + strings.Join(args, " ")
+}
+
+// runInCompileContainer runs a shell command in a compile image with tempDir mounted as the workspace.
+// It returns the combined output and exit code of the command.
+func (de *dockerExecutor) runInCompileContainer(
+	ctx context.Context,
+	compileImage, tempDir, command string,
+) (string, int64, error) {
+	// Ensure image is available (every toolchain is pulled at init; this covers images removed since)
+	resp, createErr := de.createContainerWithImageCheck(ctx, compileImage, func() (*container.Config, *container.HostConfig) {
 		containerConfig := &container.Config{
-			Image: de.compileImage,
-			Env:   []string{"CGO_ENABLED=0"}, // Disable CGO for static binary
-			Cmd: []string{
-				"sh", "-c",
-				fmt.Sprintf("go build -o %s %s",
-					filepath.Join(containerWorkspace, "binary"),
-					filepath.Join(containerWorkspace, "code.go")),
-			},
+			Image:      compileImage,
+			Env:        []string{"CGO_ENABLED=0"}, // Disable CGO for static binary
+			Cmd:        []string{"sh", "-c", command},
 			WorkingDir: containerWorkspace,
 		}
 
@@ -199,50 +337,83 @@ func (de *dockerExecutor) compileCode(ctx context.Context, tempDir string) (stri
 					Target: containerWorkspace,
 				},
 			},
-			AutoRemove: true,
+			AutoRemove: false, // Removed after reading logs so successful builds can report diagnostics
 		}
 		return containerConfig, hostConfig
 	})
 	if createErr != nil {
-		return "", fmt.Errorf("%w: %w", ErrCompilationFailed, createErr)
+		return "", 0, createErr
 	}
 
 	containerID := resp.ID
-	// Note: No manual cleanup needed - AutoRemove: true handles container removal automatically
+	defer de.removeContainer(containerID)
 
 	// Start container
-	if startErr := de.client.ContainerStart(compileCtx, containerID, container.StartOptions{}); startErr != nil {
-		return "", fmt.Errorf("%w: start container: %w", ErrCompilationFailed, startErr)
+	if startErr := de.client.ContainerStart(ctx, containerID, container.StartOptions{}); startErr != nil {
+		return "", 0, fmt.Errorf("start container: %w", startErr)
 	}
 
 	// Wait for container to finish
-	statusCh, errCh := de.client.ContainerWait(compileCtx, containerID, container.WaitConditionNotRunning)
+	statusCh, errCh := de.client.ContainerWait(ctx, containerID, container.WaitConditionNotRunning)
 
+	var statusCode int64
 	select {
 	case waitErr := <-errCh:
 		if waitErr != nil {
-			return "", fmt.Errorf("%w: wait container: %w", ErrCompilationFailed, waitErr)
+			return "", 0, fmt.Errorf("wait container: %w", waitErr)
 		}
 	case status := <-statusCh:
-		if status.StatusCode != 0 {
-			// Get stderr from container to return compilation errors
-			logs, logErr := de.getContainerLogs(compileCtx, containerID)
-			if logErr != nil {
-				return "", fmt.Errorf("%w: exit code %d", ErrCompilationFailed, status.StatusCode)
-			}
-			return "", fmt.Errorf("%w: %s", ErrCompilationFailed, logs)
+		statusCode = status.StatusCode
+	case <-ctx.Done():
+		// Kill the build so a cancelled job does not keep compiling in the background
+		de.killContainer(containerID)
+		return "", 0, fmt.Errorf("%w: compilation timeout", ErrTimeout)
+	}
+
+	output, logErr := de.getContainerLogs(ctx, containerID, false)
+	if logErr != nil {
+		de.logger.WarnContext(ctx, "failed to get compile container logs", "error", logErr)
+	}
+
+	return output, statusCode, nil
+}
+
+// vetCode runs go vet on code without executing it.
+func (de *dockerExecutor) vetCode(ctx context.Context, compileImage, code string) (*ExecutionResult, error) {
+	startTime := time.Now()
+
+	tempDir, err := os.MkdirTemp("", "docker-vet-*")
+	if err != nil {
+		return nil, fmt.Errorf("create temp directory: %w", err)
+	}
+	defer func() {
+		if cleanupErr := os.RemoveAll(tempDir); cleanupErr != nil {
+			de.logger.Error("failed to cleanup temp directory", "error", cleanupErr, "dir", tempDir)
 		}
-	case <-compileCtx.Done():
-		return "", fmt.Errorf("%w: compilation timeout", ErrTimeout)
+	}()
+
+	codeFile := filepath.Join(tempDir, "code.go")
+	if writeErr := os.WriteFile(codeFile, []byte(code), 0o600); writeErr != nil {
+		return nil, fmt.Errorf("write code file: %w", writeErr)
 	}
 
-	// Binary should now exist in tempDir
-	binaryPath := filepath.Join(tempDir, "binary")
-	if _, statErr := os.Stat(binaryPath); statErr != nil {
-		return "", fmt.Errorf("%w: binary not found after compilation", ErrCompilationFailed)
+	command := "go vet " + filepath.Join(containerWorkspace, "code.go")
+	output, statusCode, err := de.runInCompileContainer(ctx, compileImage, tempDir, command)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrContainerExecution, err)
 	}
 
-	return binaryPath, nil
+	result := &ExecutionResult{
+		Output:   truncateOutput(output, de.maxOutput),
+		ExitCode: int(statusCode),
+		Duration: time.Since(startTime).String(),
+	}
+	if statusCode != 0 {
+		result.Error = result.Output
+		result.Output = ""
+	}
+
+	return result, nil
 }
 
 // createContainerWithImageCheck creates a container, pulling the image if it doesn't exist.
@@ -273,7 +444,12 @@ func (de *dockerExecutor) createContainerWithImageCheck(
 }
 
 // executeBinary executes a compiled binary in a minimal Docker container.
-func (de *dockerExecutor) executeBinary(ctx context.Context, binaryPath string) (*ExecutionResult, error) {
+// When inputDir is set it is mounted read-only as the program's working directory.
+func (de *dockerExecutor) executeBinary(
+	ctx context.Context,
+	binaryPath, inputDir string,
+	opts RunOptions,
+) (*ExecutionResult, error) {
 	// Use parent context directly (timeout already applied)
 	execCtx := ctx
 
@@ -283,105 +459,338 @@ func (de *dockerExecutor) executeBinary(ctx context.Context, binaryPath string)
 		return nil, fmt.Errorf("read binary: %w", err)
 	}
 
-	// Calculate CPU quota (CPUPercent * CPUPeriod / 100)
-	cpuPeriod := int64(cpuPeriodMicroseconds)
-	cpuQuota := int64(de.maxCPUPercent) * cpuPeriod / cpuPercentDenominator
-	memoryBytes := int64(de.maxMemoryMB) * bytesPerKB * bytesPerKB
-
 	// Create container for execution (with image check)
 	resp, createErr := de.createContainerWithImageCheck(execCtx, de.execImage, func() (*container.Config, *container.HostConfig) {
-		containerConfig := &container.Config{
-			Image:      de.execImage,
-			Cmd:        []string{"/binary"},
-			WorkingDir: "/",
-		}
-
-		hostConfig := &container.HostConfig{
-			Resources: container.Resources{
-				Memory:    memoryBytes,
-				CPUQuota:  cpuQuota,
-				CPUPeriod: cpuPeriod,
-			},
-			AutoRemove:  false,                         // Disable auto-remove so we can get logs before cleanup
-			NetworkMode: container.NetworkMode("none"), // No network access
-		}
-		return containerConfig, hostConfig
+		return de.runContainerConfig(inputDir, opts)
 	})
 	if createErr != nil {
 		return nil, fmt.Errorf("%w: create container: %w", ErrContainerExecution, createErr)
 	}
 
 	containerID := resp.ID
+	// Auto-remove is disabled so output can be collected first; remove on the way out
+	defer de.removeContainer(containerID)
 
-	// Copy binary into container
-	if copyErr := de.copyToContainer(execCtx, containerID, binaryData); copyErr != nil {
+	// Copy binary into container, with an empty coverage directory for coverage builds
+	var dirs []string
+	if opts.Coverage {
+		dirs = append(dirs, coverContainerDir)
+	}
+	if copyErr := de.copyToContainer(execCtx, containerID, binaryData, dirs...); copyErr != nil {
 		return nil, fmt.Errorf("%w: copy binary: %w", ErrContainerExecution, copyErr)
 	}
 
+	// Deterministic output carries binary playback headers that the log driver would mangle,
+	// so read the raw streams instead of the logs
+	var streams *attachedStreams
+	if opts.Deterministic {
+		streams, err = de.attachStreams(execCtx, containerID, opts.MaxOutput)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrContainerExecution, err)
+		}
+		defer streams.close()
+	}
+
 	// Start container
 	if startErr := de.client.ContainerStart(execCtx, containerID, container.StartOptions{}); startErr != nil {
 		return nil, fmt.Errorf("%w: start container: %w", ErrContainerExecution, startErr)
 	}
 
-	// Wait for container to finish
-	statusCh, errCh := de.client.ContainerWait(execCtx, containerID, container.WaitConditionNotRunning)
+	exitCode, err := de.waitForExit(execCtx, containerID)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *ExecutionResult
+	if streams != nil {
+		result = de.playbackResult(execCtx, streams, opts.MaxOutput)
+	} else {
+		result = de.logsResult(execCtx, containerID, opts)
+	}
+	result.ExitCode = exitCode
+	result.Output, result.Styled = renderTerminal(result.Output)
+
+	if opts.Trace {
+		result.Trace = de.collectTrace(execCtx, containerID)
+	}
+	if opts.Coverage {
+		result.coverData = de.collectCoverage(execCtx, containerID)
+	}
+	if opts.Visualize {
+		result.Visualization = de.collectVisualization(execCtx, containerID)
+	}
+	result.Artifacts, result.ArtifactsTruncated = de.collectArtifacts(execCtx, containerID)
+
+	if exitCode != 0 {
+		result.Error = result.Output
+		result.Output = ""
+	}
+
+	return result, nil
+}
+
+// runContainerConfig returns the container configuration for running a compiled binary.
+func (de *dockerExecutor) runContainerConfig(inputDir string, opts RunOptions) (*container.Config, *container.HostConfig) {
+	// Calculate CPU quota (CPUPercent * CPUPeriod / 100)
+	cpuPeriod := int64(cpuPeriodMicroseconds)
+	cpuQuota := int64(opts.CPUPercent) * cpuPeriod / cpuPercentDenominator
+	memoryBytes := int64(opts.MemoryMB) * bytesPerKB * bytesPerKB
+
+	var env []string
+	if opts.Deterministic {
+		env = append(env, deterministicGODEBUG)
+	}
+	if opts.Coverage && opts.Tests == "" {
+		// Test binaries write a text profile to the same directory instead
+		env = append(env, "GOCOVERDIR="+coverContainerDir)
+	}
+	size := ttySize(opts.Columns)
+	if opts.tty() {
+		env = append(env, ttyTerm, fmt.Sprintf("COLUMNS=%d", size[1]))
+	}
+
+	containerConfig := &container.Config{
+		Image:      de.execImage,
+		Cmd:        []string{"sh", "-c", runCommand(opts)},
+		Env:        env,
+		WorkingDir: "/",
+		Tty:        opts.tty(),
+	}
+
+	hostConfig := &container.HostConfig{
+		Resources: container.Resources{
+			Memory:    memoryBytes,
+			CPUQuota:  cpuQuota,
+			CPUPeriod: cpuPeriod,
+		},
+		AutoRemove:  false,                         // Disable auto-remove so we can get logs before cleanup
+		NetworkMode: container.NetworkMode("none"), // No network access
+		Tmpfs:       artifactsTmpfs(),              // Size-capped output directory for artifacts
+	}
+	if opts.tty() {
+		hostConfig.ConsoleSize = size
+	}
+
+	// Input files are read-only, and the program starts next to them so it can open them by name
+	if inputDir != "" {
+		hostConfig.Mounts = []mount.Mount{
+			{
+				Type:     mount.TypeBind,
+				Source:   inputDir,
+				Target:   fixturesDir,
+				ReadOnly: true,
+			},
+		}
+		containerConfig.WorkingDir = fixturesDir
+	}
+
+	return containerConfig, hostConfig
+}
+
+// waitForExit waits for a started container to stop and returns its exit code.
+// On timeout or cancellation the container is killed.
+func (de *dockerExecutor) waitForExit(ctx context.Context, containerID string) (int, error) {
+	statusCh, errCh := de.client.ContainerWait(ctx, containerID, container.WaitConditionNotRunning)
 
-	var exitCode int
 	select {
 	case waitErr := <-errCh:
 		if waitErr != nil {
-			if execCtx.Err() == context.DeadlineExceeded {
-				return nil, fmt.Errorf("%w", ErrTimeout)
+			if ctx.Err() == context.DeadlineExceeded {
+				return 0, fmt.Errorf("%w", ErrTimeout)
 			}
-			return nil, fmt.Errorf("%w: wait container: %w", ErrContainerExecution, waitErr)
+			return 0, fmt.Errorf("%w: wait container: %w", ErrContainerExecution, waitErr)
 		}
+		return 0, nil
 	case status := <-statusCh:
-		exitCode = int(status.StatusCode)
-	case <-execCtx.Done():
-		// Timeout - kill container and cleanup
-		killCtx, killCancel := context.WithTimeout(context.Background(), dockerConnectionTimeout)
-		defer killCancel()
-		_ = de.client.ContainerKill(killCtx, containerID, "SIGKILL")
-		_ = de.client.ContainerRemove(killCtx, containerID, container.RemoveOptions{Force: true})
-		return nil, fmt.Errorf("%w", ErrTimeout)
-	}
-
-	// Get container logs (stdout + stderr) BEFORE removing container
-	output, logErr := de.getContainerLogs(execCtx, containerID)
+		return int(status.StatusCode), nil
+	case <-ctx.Done():
+		// Timeout or cancellation - kill container and cleanup
+		de.killContainer(containerID)
+		return 0, fmt.Errorf("%w", ErrTimeout)
+	}
+}
+
+// logsResult builds a result from the container logs (stdout + stderr combined).
+func (de *dockerExecutor) logsResult(ctx context.Context, containerID string, opts RunOptions) *ExecutionResult {
+	output, logErr := de.getContainerLogs(ctx, containerID, opts.tty())
 	if logErr != nil {
-		de.logger.WarnContext(execCtx, "failed to get container logs", "error", logErr)
+		de.logger.WarnContext(ctx, "failed to get container logs", "error", logErr)
 		output = ""
 	}
 
-	// Cleanup container after getting logs
-	removeCtx, removeCancel := context.WithTimeout(context.Background(), dockerConnectionTimeout)
-	defer removeCancel()
-	if removeErr := de.client.ContainerRemove(removeCtx, containerID, container.RemoveOptions{Force: true}); removeErr != nil {
-		de.logger.WarnContext(removeCtx, "failed to remove execution container", "error", removeErr, "container", containerID)
+	return &ExecutionResult{Output: truncateOutput(output, opts.MaxOutput)}
+}
+
+// playbackResult builds a result from the raw faketime streams, with virtual timestamps.
+func (de *dockerExecutor) playbackResult(ctx context.Context, streams *attachedStreams, maxOutput int) *ExecutionResult {
+	if waitErr := streams.wait(ctx); waitErr != nil {
+		de.logger.WarnContext(ctx, "failed to read container output", "error", waitErr)
 	}
 
-	// Truncate output if needed
-	output = de.truncateOutput(output)
+	events := mergePlayback(
+		parsePlayback(streams.stdout.Bytes(), streamStdout),
+		parsePlayback(streams.stderr.Bytes(), streamStderr),
+	)
 
-	result := &ExecutionResult{
-		ExitCode: exitCode,
-		Output:   output,
+	return &ExecutionResult{
+		Output: truncateOutput(playbackOutput(events), maxOutput),
+		Events: events,
 	}
+}
 
-	if exitCode != 0 {
-		result.Error = output
-		result.Output = ""
+// collectTrace copies the runtime trace out of a stopped container and parses it.
+// A program that exits without returning from main leaves no usable trace, so failures are logged and yield nil.
+func (de *dockerExecutor) collectTrace(ctx context.Context, containerID string) *Timeline {
+	files, err := de.copyFromContainer(ctx, containerID, traceContainerPath, maxTraceBytes)
+	if err != nil {
+		de.logger.WarnContext(ctx, "failed to copy trace from container", "error", err)
+		return nil
 	}
 
-	return result, nil
+	timeline, err := parseTrace(bytes.NewReader(files[path.Base(traceContainerPath)]))
+	if err != nil {
+		de.logger.WarnContext(ctx, "failed to parse trace", "error", err)
+		return nil
+	}
+
+	return timeline
+}
+
+// collectCoverage copies the coverage data out of a stopped container.
+// Programs that crash write no counters, so failures are logged and yield nil.
+func (de *dockerExecutor) collectCoverage(ctx context.Context, containerID string) map[string][]byte {
+	files, err := de.copyFromContainer(ctx, containerID, coverContainerDir, maxCoverageBytes)
+	if err != nil {
+		de.logger.WarnContext(ctx, "failed to copy coverage data from container", "error", err)
+		return nil
+	}
+	return files
+}
+
+// convertCoverage reads the coverage data of a run. A test binary writes a text profile; a
+// program writes raw counters, which are converted with go tool covdata in the compile image
+// that built it, so the format always matches the toolchain. Failures are logged and yield nil.
+func (de *dockerExecutor) convertCoverage(ctx context.Context, compileImage, tempDir string, files map[string][]byte) *Coverage {
+	if len(files) == 0 {
+		return nil
+	}
+
+	profile, ok := files[coverProfileFile]
+	if !ok {
+		var err error
+		if profile, err = de.runCovdata(ctx, compileImage, tempDir, files); err != nil {
+			de.logger.WarnContext(ctx, "failed to convert coverage data", "error", err)
+			return nil
+		}
+	}
+
+	coverage, err := parseCoverProfile(profile)
+	if err != nil {
+		de.logger.WarnContext(ctx, "failed to parse coverage profile", "error", err)
+		return nil
+	}
+
+	return coverage
+}
+
+// runCovdata writes a program's counters to the workspace and converts them to a text profile.
+func (de *dockerExecutor) runCovdata(ctx context.Context, compileImage, tempDir string, files map[string][]byte) ([]byte, error) {
+	dataDir := filepath.Join(tempDir, coverDataDir)
+	if err := os.Mkdir(dataDir, 0o700); err != nil {
+		return nil, fmt.Errorf("create coverage directory: %w", err)
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dataDir, filepath.Base(name)), data, 0o600); err != nil {
+			return nil, fmt.Errorf("write coverage data: %w", err)
+		}
+	}
+
+	output, statusCode, err := de.runInCompileContainer(ctx, compileImage, tempDir, coverConvertCommand)
+	if err != nil {
+		return nil, err
+	}
+	if statusCode != 0 {
+		return nil, fmt.Errorf("go tool covdata exited with code %d: %s", statusCode, output)
+	}
+
+	profile, err := os.ReadFile(filepath.Join(tempDir, coverProfileFile))
+	if err != nil {
+		return nil, fmt.Errorf("read coverage profile: %w", err)
+	}
+	return profile, nil
+}
+
+// collectVisualization copies the recorded steps out of a stopped container and parses them.
+// Programs that never reach a recorded statement leave no steps file, so failures are logged and yield nil.
+func (de *dockerExecutor) collectVisualization(ctx context.Context, containerID string) *Visualization {
+	files, err := de.copyFromContainer(ctx, containerID, vizContainerPath, maxVizBytes)
+	if err != nil {
+		de.logger.WarnContext(ctx, "failed to copy visualization steps from container", "error", err)
+		return nil
+	}
+
+	viz, err := parseVisualization(files[path.Base(vizContainerPath)])
+	if err != nil {
+		de.logger.WarnContext(ctx, "failed to parse visualization steps", "error", err)
+		return nil
+	}
+
+	return viz
+}
+
+// collectArtifacts copies the archived artifacts directory out of a stopped container.
+// It returns nil when the program wrote no files, and the number of files over the limit.
+func (de *dockerExecutor) collectArtifacts(ctx context.Context, containerID string) ([]Artifact, int) {
+	files, err := de.copyFromContainer(ctx, containerID, artifactsArchive, maxArtifactsArchiveBytes)
+	if err != nil {
+		// A program killed before exiting never archives its artifacts
+		de.logger.DebugContext(ctx, "no artifacts archive in container", "error", err)
+		return nil, 0
+	}
+
+	artifacts, truncated, err := parseArtifacts(files[path.Base(artifactsArchive)])
+	if err != nil {
+		de.logger.WarnContext(ctx, "failed to parse artifacts", "error", err)
+		return nil, 0
+	}
+	if len(artifacts) == 0 {
+		return nil, 0
+	}
+
+	return artifacts, truncated
+}
+
+// removeContainer removes a stopped container, logging failures.
+func (de *dockerExecutor) removeContainer(containerID string) {
+	removeCtx, removeCancel := context.WithTimeout(context.Background(), dockerConnectionTimeout)
+	defer removeCancel()
+	removeErr := de.client.ContainerRemove(removeCtx, containerID, container.RemoveOptions{Force: true})
+	if removeErr != nil && !errdefs.IsNotFound(removeErr) {
+		de.logger.WarnContext(removeCtx, "failed to remove container", "error", removeErr, "container", containerID)
+	}
 }
 
-// copyToContainer copies binary data into a container.
-func (de *dockerExecutor) copyToContainer(ctx context.Context, containerID string, data []byte) error {
+// killContainer force-kills and removes a container.
+// It uses a fresh context because the caller's context is usually already done.
+func (de *dockerExecutor) killContainer(containerID string) {
+	killCtx, killCancel := context.WithTimeout(context.Background(), dockerConnectionTimeout)
+	defer killCancel()
+	_ = de.client.ContainerKill(killCtx, containerID, "SIGKILL")
+	_ = de.client.ContainerRemove(killCtx, containerID, container.RemoveOptions{Force: true})
+}
+
+// copyToContainer copies binary data into a container, creating any extra directories.
+func (de *dockerExecutor) copyToContainer(ctx context.Context, containerID string, data []byte, dirs ...string) error {
 	// Create a tar archive containing the binary
 	var buf bytes.Buffer
 	tw := tar.NewWriter(&buf)
 
+	for _, dir := range dirs {
+		if err := tw.WriteHeader(&tar.Header{Name: dir + "/", Typeflag: tar.TypeDir, Mode: dirFileMode}); err != nil {
+			return fmt.Errorf("write tar header: %w", err)
+		}
+	}
+
 	header := &tar.Header{
 		Name: "/binary",
 		Mode: binaryFileMode,
@@ -404,9 +813,50 @@ func (de *dockerExecutor) copyToContainer(ctx context.Context, containerID strin
 	return de.client.CopyToContainer(ctx, containerID, "/", &buf, container.CopyToContainerOptions{})
 }
 
+// copyFromContainer reads a file, or the files directly inside a directory, out of a container.
+// Files are keyed by base name; it fails if they add up to more than limit bytes.
+func (de *dockerExecutor) copyFromContainer(
+	ctx context.Context,
+	containerID, srcPath string,
+	limit int64,
+) (map[string][]byte, error) {
+	reader, _, err := de.client.CopyFromContainer(ctx, containerID, srcPath)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	files := map[string][]byte{}
+	remaining := limit
+	tr := tar.NewReader(reader)
+	for {
+		header, nextErr := tr.Next()
+		if errors.Is(nextErr, io.EOF) {
+			return files, nil
+		}
+		if nextErr != nil {
+			return nil, fmt.Errorf("read tar header: %w", nextErr)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if header.Size > remaining {
+			return nil, fmt.Errorf("%s exceeds %d bytes", srcPath, limit)
+		}
+		remaining -= header.Size
+
+		data, readErr := io.ReadAll(tr)
+		if readErr != nil {
+			return nil, fmt.Errorf("read tar data: %w", readErr)
+		}
+		files[path.Base(header.Name)] = data
+	}
+}
+
 // getContainerLogs retrieves stdout and stderr from a container.
 // Docker logs use an 8-byte header format, so we use stdcopy to properly demultiplex.
-func (de *dockerExecutor) getContainerLogs(ctx context.Context, containerID string) (string, error) {
+// Containers with a pseudo-terminal have a single raw stream instead.
+func (de *dockerExecutor) getContainerLogs(ctx context.Context, containerID string, tty bool) (string, error) {
 	reader, logErr := de.client.ContainerLogs(ctx, containerID, container.LogsOptions{
 		ShowStdout: true,
 		ShowStderr: true,
@@ -416,9 +866,16 @@ func (de *dockerExecutor) getContainerLogs(ctx context.Context, containerID stri
 	}
 	defer reader.Close()
 
+	var output bytes.Buffer
+	if tty {
+		if _, copyErr := io.Copy(&output, reader); copyErr != nil {
+			return "", copyErr
+		}
+		return output.String(), nil
+	}
+
 	// Use stdcopy to properly demultiplex Docker's 8-byte header format
 	// Combine both stdout and stderr into a single buffer
-	var output bytes.Buffer
 	if _, copyErr := stdcopy.StdCopy(&output, &output, reader); copyErr != nil {
 		return "", copyErr
 	}
@@ -426,10 +883,10 @@ func (de *dockerExecutor) getContainerLogs(ctx context.Context, containerID stri
 	return output.String(), nil
 }
 
-// truncateOutput truncates output if it exceeds maximum size.
-func (de *dockerExecutor) truncateOutput(output string) string {
-	if len(output) <= de.maxOutput {
+// truncateOutput truncates output if it exceeds maxOutput bytes.
+func truncateOutput(output string, maxOutput int) string {
+	if len(output) <= maxOutput {
 		return output
 	}
-	return output[:de.maxOutput] + "\n... (output truncated)"
+	return output[:maxOutput] + "\n... (output truncated)"
 }
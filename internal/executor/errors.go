@@ -12,4 +12,14 @@ var (
 	ErrCompilationFailed = errors.New("compilation failed")
 	// ErrTimeout is returned when execution exceeds the timeout.
 	ErrTimeout = errors.New("execution timeout exceeded")
+	// ErrUnknownToolchain is returned when a request selects a toolchain that is not registered.
+	ErrUnknownToolchain = errors.New("unknown toolchain")
+	// ErrTraceUnsupported is returned when trace mode is requested with a toolchain too old to trace.
+	ErrTraceUnsupported = errors.New("trace mode not supported")
+	// ErrTestsUnsupported is returned when a test run asks for a mode that only works for programs.
+	ErrTestsUnsupported = errors.New("mode not supported with tests")
+	// ErrInvalidInputFile is returned when input files are malformed or exceed the limits.
+	ErrInvalidInputFile = errors.New("invalid input file")
+	// ErrInvalidExpectation is returned when an expected output check is malformed.
+	ErrInvalidExpectation = errors.New("invalid output expectation")
 )
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"maps"
 	"time"
 )
 
@@ -21,6 +22,34 @@ type ExecutionResult struct {
 	Error    string `json:"error,omitempty"`
 	ExitCode int    `json:"exitCode"`
 	Duration string `json:"duration"`
+	// Toolchain is the name of the Go toolchain that compiled the program.
+	Toolchain string `json:"toolchain,omitempty"`
+	// Events holds timestamped output in deterministic mode so clients can replay it.
+	Events []OutputEvent `json:"events,omitempty"`
+	// Insight holds escape analysis, inlining and assembly listings in insight mode.
+	Insight *Insight `json:"insight,omitempty"`
+	// Trace holds the goroutine timeline recorded in trace mode.
+	Trace *Timeline `json:"trace,omitempty"`
+	// Coverage holds per-line hit counts in coverage mode.
+	Coverage *Coverage `json:"coverage,omitempty"`
+	// Visualization holds local variable state after each statement in visualization mode.
+	Visualization *Visualization `json:"visualization,omitempty"`
+	// Artifacts holds the files the program wrote to /out.
+	Artifacts []Artifact `json:"artifacts,omitempty"`
+	// ArtifactsTruncated is the number of files left out of Artifacts once the limit was reached.
+	ArtifactsTruncated int `json:"artifactsTruncated,omitempty"`
+	// Styled holds the rendered output, or the error output of a failed run, as styled lines
+	// when the program used ANSI colors.
+	Styled []StyledLine `json:"styled,omitempty"`
+	// Check holds the comparison with the expected output, when one was given.
+	Check *OutputCheck `json:"check,omitempty"`
+	// Failure holds whether the run failed the expected way, when a failure was expected.
+	Failure *FailureCheck `json:"failure,omitempty"`
+
+	// compileFailed is set when the compiler rejected the code, as opposed to the build not running.
+	compileFailed bool
+	// coverData holds the files the run wrote to the coverage directory, until they are converted.
+	coverData map[string][]byte
 }
 
 // CodeExecutor handles execution of Go code with security restrictions via Docker.
@@ -29,10 +58,14 @@ type CodeExecutor struct {
 	maxOutput     int
 	maxMemoryMB   int
 	maxCPUPercent int
+	limits        Limits
 	compileImage  string
 	execImage     string
 	logger        *slog.Logger
 	dockerExec    *dockerExecutor
+
+	toolchains       map[string]string // toolchain name -> compile image
+	defaultToolchain string
 }
 
 // NewCodeExecutor creates a new code executor with security defaults using Docker.
@@ -42,22 +75,33 @@ func NewCodeExecutor(opts ...ExecutorOption) (*CodeExecutor, error) {
 		maxOutput:     defaultMaxOutput,
 		maxMemoryMB:   defaultMaxMemoryMB,
 		maxCPUPercent: defaultMaxCPUPercent,
-		compileImage:  defaultCompileImage,
 		execImage:     defaultExecImage,
 		logger:        slog.Default(),
+
+		toolchains:       maps.Clone(defaultToolchains),
+		defaultToolchain: defaultToolchain,
 	}
 
 	// Apply options
 	for _, opt := range opts {
 		opt(executor)
 	}
+	executor.defaultLimits()
+
+	// An explicit compile image overrides the default toolchain's image
+	if executor.compileImage != "" {
+		executor.toolchains[executor.defaultToolchain] = executor.compileImage
+	}
+	compileImage, ok := executor.toolchains[executor.defaultToolchain]
+	if !ok {
+		return nil, fmt.Errorf("%w: default %s", ErrUnknownToolchain, executor.defaultToolchain)
+	}
+	executor.compileImage = compileImage
 
 	// Initialize Docker executor
 	dockerExec, err := newDockerExecutor(
-		executor.compileImage,
+		executor.compileImages(),
 		executor.execImage,
-		executor.maxMemoryMB,
-		executor.maxCPUPercent,
 		executor.maxOutput,
 		executor.timeout,
 		executor.logger,
@@ -72,7 +116,9 @@ func NewCodeExecutor(opts ...ExecutorOption) (*CodeExecutor, error) {
 		"timeout", executor.timeout,
 		"max_memory_mb", executor.maxMemoryMB,
 		"max_cpu_percent", executor.maxCPUPercent,
+		"limits", executor.limits,
 		"compile_image", executor.compileImage,
+		"default_toolchain", executor.defaultToolchain,
 		"exec_image", executor.execImage,
 	)
 
@@ -91,21 +137,127 @@ func (e *CodeExecutor) ExecuteSnippet(ctx context.Context, code string) (*Execut
 
 // ExecuteWithOptions runs Go code with options for snippet handling.
 func (e *CodeExecutor) ExecuteWithOptions(ctx context.Context, code string, isSnippet bool) (*ExecutionResult, error) {
+	return e.Run(ctx, code, RunOptions{Snippet: isSnippet})
+}
+
+// RunOptions controls a single execution.
+type RunOptions struct {
+	// Snippet forces the code to be wrapped before execution.
+	Snippet bool
+	// Timeout overrides the executor's default timeout when non-zero.
+	Timeout time.Duration
+	// MaxTimeout replaces the executor's timeout limit when non-zero, for callers that bound
+	// the run themselves, such as asynchronous jobs.
+	MaxTimeout time.Duration
+	// MemoryMB, CPUPercent and MaxOutput override the executor's resource defaults when non-zero.
+	// Like Timeout, they are clamped to the executor's Limits.
+	MemoryMB   int
+	CPUPercent int
+	MaxOutput  int
+	// Expect compares the output with an expected output and reports the result in Check.
+	Expect *OutputExpectation
+	// ExpectFailure checks that the program fails to compile, panics or deadlocks and reports
+	// the result in Failure.
+	ExpectFailure *FailureExpectation
+	// Toolchain selects a registered Go toolchain by name; empty uses the default.
+	Toolchain string
+	// Deterministic runs the program on a virtual clock, like the Go playground:
+	// time starts at a fixed epoch, sleeps return instantly, and math/rand is seeded.
+	Deterministic bool
+	// Insight builds with -gcflags=-m=2 and returns escape analysis and inlining decisions per line.
+	Insight bool
+	// Assembly adds the generated assembly, grouped by function, to the insight (implies Insight).
+	Assembly bool
+	// Trace runs the program under runtime/trace and returns a goroutine and GC timeline.
+	Trace bool
+	// Coverage builds with -cover and returns how often each line of the code ran.
+	Coverage bool
+	// Tests is the source of a test file in the code's package. When set, the code is built
+	// with go test -c and its tests run verbosely instead of the program; with Coverage, the
+	// coverage is that of the tests.
+	Tests string
+	// Visualize records the local variables in scope after each statement, for stepping through a run.
+	Visualize bool
+	// Files are mounted read-only at /fixtures, which is the program's working directory.
+	Files []InputFile
+	// TTY runs the program on a pseudo-terminal, so libraries that check for one emit colors.
+	// Deterministic mode reads the raw output streams and runs without one.
+	TTY bool
+	// Columns sets the pseudo-terminal width; zero uses 80 columns.
+	Columns int
+}
+
+// insight reports whether compiler diagnostics were requested.
+func (o RunOptions) insight() bool {
+	return o.Insight || o.Assembly
+}
+
+// tty reports whether the program runs on a pseudo-terminal.
+func (o RunOptions) tty() bool {
+	return o.TTY && !o.Deterministic
+}
+
+// Run executes Go code with per-call options.
+func (e *CodeExecutor) Run(ctx context.Context, code string, opts RunOptions) (*ExecutionResult, error) {
+	toolchain, compileImage, err := e.resolveToolchain(opts.Toolchain)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Trace {
+		if err := checkTraceToolchain(toolchain); err != nil {
+			return nil, err
+		}
+	}
+	if err := opts.ValidateTests(); err != nil {
+		return nil, err
+	}
+
 	// Prepare code for execution (wrap if needed)
-	executableCode := PrepareForExecution(code, isSnippet)
+	executableCode := PrepareForExecution(code, opts.Snippet)
+
+	opts = e.resolveLimits(opts)
 
 	// Create execution context with timeout
-	execCtx, cancel := context.WithTimeout(ctx, e.timeout)
+	execCtx, cancel := context.WithTimeout(ctx, opts.Timeout)
 	defer cancel()
 
 	// Execute in Docker container (includes compilation and execution)
-	result, err := e.dockerExec.execute(execCtx, executableCode)
+	result, err := e.dockerExec.execute(execCtx, compileImage, executableCode, opts)
 	if err != nil {
 		return nil, fmt.Errorf("execute code: %w", err)
 	}
+	result.Toolchain = toolchain
+
+	// Report positions against the submitted code rather than the wrapped program
+	sm := newSourceMap(code, executableCode)
+	if result.Insight != nil {
+		result.Insight.mapToSource(sm)
+	}
+	if result.Coverage != nil {
+		result.Coverage.mapToSource(sm)
+	}
+	if result.Visualization != nil {
+		result.Visualization.mapToSource(sm)
+	}
+
+	if opts.Expect != nil {
+		output, exitCode := result.Output, result.ExitCode
+		switch {
+		case opts.ExpectFailure != nil:
+			// The failure is checked on its own; the output is what was printed before it
+			exitCode = 0
+		case exitCode != 0:
+			output = result.Error
+		}
+		result.Check = opts.Expect.Check(output, exitCode)
+	}
+	if opts.ExpectFailure != nil {
+		result.Failure = opts.ExpectFailure.Check(result)
+	}
 
 	e.logger.DebugContext(ctx, "code execution completed",
 		"duration", result.Duration,
+		"toolchain", toolchain,
 		"exit_code", result.ExitCode,
 		"output_length", len(result.Output),
 	)
@@ -113,6 +265,27 @@ func (e *CodeExecutor) ExecuteWithOptions(ctx context.Context, code string, isSn
 	return result, nil
 }
 
+// Check runs go vet on the code without executing it.
+func (e *CodeExecutor) Check(ctx context.Context, code string, opts RunOptions) (*ExecutionResult, error) {
+	toolchain, compileImage, err := e.resolveToolchain(opts.Toolchain)
+	if err != nil {
+		return nil, err
+	}
+
+	executableCode := PrepareForExecution(code, opts.Snippet)
+
+	checkCtx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	result, err := e.dockerExec.vetCode(checkCtx, compileImage, executableCode)
+	if err != nil {
+		return nil, fmt.Errorf("check code: %w", err)
+	}
+	result.Toolchain = toolchain
+
+	return result, nil
+}
+
 // Cleanup cleans up resources (containers auto-remove, so this is mostly a no-op for compatibility).
 func (e *CodeExecutor) Cleanup() error {
 	// Docker containers use AutoRemove: true, so cleanup is automatic
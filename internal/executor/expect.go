@@ -0,0 +1,138 @@
+package executor
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Whitespace normalisation modes for comparing output.
+const (
+	// WhitespaceTrim ignores leading and trailing whitespace of the whole output, like Go example tests.
+	WhitespaceTrim = "trim"
+	// WhitespaceExact compares output byte for byte, apart from line endings.
+	WhitespaceExact = "exact"
+	// WhitespaceCollapse also treats any run of spaces and tabs as one space and ignores trailing spaces.
+	WhitespaceCollapse = "collapse"
+)
+
+// Diff line operations.
+const (
+	DiffEqual    = "="
+	DiffExpected = "-" // Line is only in the expected output
+	DiffActual   = "+" // Line is only in the actual output
+)
+
+// maxDiffCells bounds the work of the line diff; larger outputs are reported as fully different.
+const maxDiffCells = 1 << 20
+
+// OutputExpectation is the output a run should print and how to compare it.
+type OutputExpectation struct {
+	Output     string `json:"output"`
+	Whitespace string `json:"whitespace,omitempty"` // "trim" (default), "exact" or "collapse"
+	Unordered  bool   `json:"unordered,omitempty"`  // Lines may appear in any order
+}
+
+// OutputCheck is the result of comparing a run's output with the expected output.
+type OutputCheck struct {
+	Passed bool       `json:"passed"`
+	Diff   []DiffLine `json:"diff,omitempty"` // Normalised expected and actual lines, when the check failed
+}
+
+// DiffLine is one line of a line diff.
+type DiffLine struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// Validate checks the expectation's normalisation mode.
+func (x *OutputExpectation) Validate() error {
+	switch x.Whitespace {
+	case "", WhitespaceTrim, WhitespaceExact, WhitespaceCollapse:
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown whitespace mode %q", ErrInvalidExpectation, x.Whitespace)
+	}
+}
+
+// Check compares a run's output with the expectation. A run that exited with an error fails
+// even when its output matches.
+func (x *OutputExpectation) Check(output string, exitCode int) *OutputCheck {
+	expected := x.normalize(x.Output)
+	actual := x.normalize(output)
+
+	if exitCode == 0 && slices.Equal(expected, actual) {
+		return &OutputCheck{Passed: true}
+	}
+	return &OutputCheck{Diff: diffLines(expected, actual)}
+}
+
+// normalize splits output into lines after applying the whitespace mode and ordering.
+func (x *OutputExpectation) normalize(output string) []string {
+	output = strings.ReplaceAll(output, "\r\n", "\n")
+	if x.Whitespace != WhitespaceExact {
+		output = strings.TrimSpace(output)
+	}
+	if output == "" {
+		return nil
+	}
+
+	lines := strings.Split(output, "\n")
+	if x.Whitespace == WhitespaceCollapse {
+		for i, line := range lines {
+			lines[i] = strings.Join(strings.Fields(line), " ")
+		}
+	}
+	if x.Unordered {
+		slices.Sort(lines)
+	}
+	return lines
+}
+
+// diffLines returns a line diff of expected and actual output based on their longest common subsequence.
+func diffLines(expected, actual []string) []DiffLine {
+	n, m := len(expected), len(actual)
+	if n*m > maxDiffCells {
+		diff := make([]DiffLine, 0, n+m)
+		for _, line := range expected {
+			diff = append(diff, DiffLine{Op: DiffExpected, Text: line})
+		}
+		for _, line := range actual {
+			diff = append(diff, DiffLine{Op: DiffActual, Text: line})
+		}
+		return diff
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of expected[i:] and actual[j:]
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if expected[i] == actual[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	diff := make([]DiffLine, 0, max(n, m))
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && expected[i] == actual[j]:
+			diff = append(diff, DiffLine{Op: DiffEqual, Text: expected[i]})
+			i++
+			j++
+		case j == m || (i < n && lcs[i+1][j] >= lcs[i][j+1]):
+			diff = append(diff, DiffLine{Op: DiffExpected, Text: expected[i]})
+			i++
+		default:
+			diff = append(diff, DiffLine{Op: DiffActual, Text: actual[j]})
+			j++
+		}
+	}
+	return diff
+}
@@ -0,0 +1,22 @@
+package executor
+
+// RenderTerminal exposes renderTerminal to the external tests.
+var RenderTerminal = renderTerminal
+
+// ParseCoverProfile exposes parseCoverProfile to the external tests.
+var ParseCoverProfile = parseCoverProfile
+
+// ResolveLimits resolves a run's options on an executor with the default resources and limits.
+func ResolveLimits(opts RunOptions) RunOptions {
+	e := &CodeExecutor{
+		timeout:       defaultTimeout,
+		maxOutput:     defaultMaxOutput,
+		maxMemoryMB:   defaultMaxMemoryMB,
+		maxCPUPercent: defaultMaxCPUPercent,
+	}
+	e.defaultLimits()
+	return e.resolveLimits(opts)
+}
+
+// ParseArtifacts exposes parseArtifacts to the external tests.
+var ParseArtifacts = parseArtifacts
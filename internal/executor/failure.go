@@ -0,0 +1,101 @@
+package executor
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Kinds of failure a run can be expected to end in.
+const (
+	FailureCompileError = "compile-error"
+	FailurePanic        = "panic"
+	FailureDeadlock     = "deadlock"
+)
+
+// deadlockMarker is what the Go runtime prints when every goroutine is blocked.
+const deadlockMarker = "fatal error: all goroutines are asleep - deadlock!"
+
+// panicPattern matches the first line of an unrecovered panic.
+var panicPattern = regexp.MustCompile(`(?m)^panic: `)
+
+// failureDescriptions names each kind of failure in check results.
+var failureDescriptions = map[string]string{
+	FailureCompileError: "a compile error",
+	FailurePanic:        "a panic",
+	FailureDeadlock:     "a deadlock",
+}
+
+// FailureExpectation is how a program that demonstrates an error is expected to fail.
+type FailureExpectation struct {
+	Kind    string `json:"kind"`              // "compile-error", "panic" or "deadlock"
+	Message string `json:"message,omitempty"` // Text the error output must contain
+}
+
+// FailureCheck is the result of comparing how a run ended with the expected failure.
+type FailureCheck struct {
+	Passed bool   `json:"passed"`
+	Reason string `json:"reason"` // "failed as expected" or what happened instead
+}
+
+// Validate checks the expected failure kind.
+func (x *FailureExpectation) Validate() error {
+	if _, ok := failureDescriptions[x.Kind]; !ok {
+		return fmt.Errorf("%w: unknown failure kind %q", ErrInvalidExpectation, x.Kind)
+	}
+	return nil
+}
+
+// Check reports whether a run failed the expected way. A run that succeeds fails the check.
+func (x *FailureExpectation) Check(result *ExecutionResult) *FailureCheck {
+	expected := failureDescriptions[x.Kind]
+	if x.Message != "" {
+		expected += fmt.Sprintf(" mentioning %q", x.Message)
+	}
+
+	if actual := failureKind(result); actual != x.Kind {
+		return &FailureCheck{Reason: fmt.Sprintf("expected %s, but %s", expected, describeOutcome(actual, result.ExitCode))}
+	}
+	if !strings.Contains(result.Error+"\n"+result.Output, x.Message) {
+		return &FailureCheck{Reason: fmt.Sprintf("expected %s, but the error output does not mention it", expected)}
+	}
+
+	return &FailureCheck{Passed: true, Reason: "failed as expected with " + expected}
+}
+
+// failureKind classifies how a run failed, or returns "" for a run that did not compile-error,
+// panic or deadlock. Pseudo-terminal runs print errors with the output, so both are searched.
+func failureKind(result *ExecutionResult) string {
+	if result.compileFailed {
+		return FailureCompileError
+	}
+	if result.ExitCode == 0 {
+		return ""
+	}
+
+	output := result.Error + "\n" + result.Output
+	switch {
+	case strings.Contains(output, deadlockMarker):
+		return FailureDeadlock
+	case panicPattern.MatchString(output):
+		return FailurePanic
+	default:
+		return ""
+	}
+}
+
+// describeOutcome says how a run ended for a failed check.
+func describeOutcome(kind string, exitCode int) string {
+	switch kind {
+	case FailureCompileError:
+		return "the program did not compile"
+	case FailurePanic:
+		return "the program panicked"
+	case FailureDeadlock:
+		return "the program deadlocked"
+	}
+	if exitCode != 0 {
+		return fmt.Sprintf("the program exited with code %d", exitCode)
+	}
+	return "the program ran successfully"
+}
@@ -0,0 +1,93 @@
+package executor
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Input files are mounted read-only into the run container, which starts in their directory
+// so programs can open them by name.
+const (
+	// fixturesDir is where input files are mounted in the run container.
+	fixturesDir = "/fixtures"
+	// maxInputFiles bounds the number of input files per run.
+	maxInputFiles = 10
+	// maxInputBytes bounds the combined size of input files per run.
+	maxInputBytes = bytesPerKB * bytesPerKB
+	// inputEncodingBase64 marks input file content that is base64-encoded.
+	inputEncodingBase64 = "base64"
+	// fixtureFileMode makes input files world-readable; the mount, not the mode, keeps them read-only.
+	fixtureFileMode = 0o644
+)
+
+// InputFile is a file made available to the program, e.g. data.txt for a word count example.
+type InputFile struct {
+	Name     string `json:"name"`
+	Content  string `json:"content"`
+	Encoding string `json:"encoding,omitempty"` // "base64" for binary content; text otherwise
+}
+
+// data returns the decoded file contents.
+func (f InputFile) data() ([]byte, error) {
+	switch f.Encoding {
+	case "":
+		return []byte(f.Content), nil
+	case inputEncodingBase64:
+		return base64.StdEncoding.DecodeString(f.Content)
+	default:
+		return nil, fmt.Errorf("unknown encoding %q", f.Encoding)
+	}
+}
+
+// ValidateInputFiles checks input files against the per-run limits.
+func ValidateInputFiles(files []InputFile) error {
+	if len(files) > maxInputFiles {
+		return fmt.Errorf("%w: at most %d files are allowed", ErrInvalidInputFile, maxInputFiles)
+	}
+
+	total := 0
+	seen := map[string]bool{}
+	for _, f := range files {
+		if !filepath.IsLocal(f.Name) {
+			return fmt.Errorf("%w: %q must be a relative path", ErrInvalidInputFile, f.Name)
+		}
+		if seen[filepath.Clean(f.Name)] {
+			return fmt.Errorf("%w: %q is listed twice", ErrInvalidInputFile, f.Name)
+		}
+		seen[filepath.Clean(f.Name)] = true
+
+		data, err := f.data()
+		if err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrInvalidInputFile, f.Name, err)
+		}
+		total += len(data)
+	}
+
+	if total > maxInputBytes {
+		return fmt.Errorf("%w: files exceed %d bytes", ErrInvalidInputFile, maxInputBytes)
+	}
+
+	return nil
+}
+
+// writeInputFiles writes input files under dir.
+func writeInputFiles(dir string, files []InputFile) error {
+	if err := ValidateInputFiles(files); err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		data, _ := f.data() // Validated above
+		target := filepath.Join(dir, f.Name)
+		if err := os.MkdirAll(filepath.Dir(target), binaryFileMode); err != nil {
+			return fmt.Errorf("create input directory: %w", err)
+		}
+		if err := os.WriteFile(target, data, fixtureFileMode); err != nil {
+			return fmt.Errorf("write input file: %w", err)
+		}
+	}
+
+	return nil
+}
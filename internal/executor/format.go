@@ -0,0 +1,13 @@
+package executor
+
+import "go/format"
+
+// Format formats Go code with gofmt rules.
+// Partial sources such as statement lists are accepted, so snippets can be formatted as-is.
+func Format(code string) (string, error) {
+	formatted, err := format.Source([]byte(code))
+	if err != nil {
+		return "", err
+	}
+	return string(formatted), nil
+}
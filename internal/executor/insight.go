@@ -0,0 +1,203 @@
+package executor
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Insight builds add compiler diagnostics to the normal build.
+const (
+	// insightGCFlags reports escape analysis and inlining decisions with explanations.
+	insightGCFlags = "-m=2"
+	// assemblyGCFlag additionally prints the generated assembly.
+	assemblyGCFlag = "-S"
+	// maxAsmInstructions bounds the assembly listing returned to clients.
+	maxAsmInstructions = 5000
+)
+
+// Annotation kinds reported by insight mode.
+const (
+	AnnotationCanInline    = "can-inline"
+	AnnotationCannotInline = "cannot-inline"
+	AnnotationInlinedCall  = "inlined-call"
+	AnnotationMovedToHeap  = "moved-to-heap"
+	AnnotationEscapes      = "escapes"
+	AnnotationNoEscape     = "does-not-escape"
+	AnnotationLeakingParam = "leaking-param"
+	AnnotationInfo         = "info"
+)
+
+var (
+	// diagnosticRegex matches compiler diagnostics for the program source, e.g. "./code.go:8:2: moved to heap: p".
+	diagnosticRegex = regexp.MustCompile(`^(?:\S*/)?code\.go:(\d+):(\d+): (.*)$`)
+	// asmHeaderRegex matches the start of a function listing, e.g. "main.main STEXT size=98 args=0x0 locals=0x28".
+	asmHeaderRegex = regexp.MustCompile(`^(\S+) STEXT\b.*\bsize=(\d+)`)
+	// asmInstructionRegex matches an instruction line, e.g. "\t0x0000 00000 (/workspace/code.go:7)\tTEXT\tmain.main(SB)".
+	asmInstructionRegex = regexp.MustCompile(`^\s+0x[0-9a-f]+ (\d+) \(([^)]*?):(\d+)\)\s+(\S+)\s*(.*)$`)
+)
+
+// Insight holds compiler decisions for the submitted program.
+type Insight struct {
+	Annotations []Annotation  `json:"annotations"`
+	Assembly    []AsmFunction `json:"assembly,omitempty"`
+	Truncated   bool          `json:"truncated,omitempty"` // Assembly listing was cut at the instruction limit
+}
+
+// Annotation is an escape analysis or inlining decision attached to a source line.
+type Annotation struct {
+	Line    int      `json:"line"`
+	Column  int      `json:"column"`
+	Kind    string   `json:"kind"`
+	Message string   `json:"message"`
+	Details []string `json:"details,omitempty"` // Explanation lines printed by -m=2
+}
+
+// AsmFunction is the assembly listing for one function.
+type AsmFunction struct {
+	Name         string           `json:"name"`
+	Size         int              `json:"size"`
+	Instructions []AsmInstruction `json:"instructions"`
+}
+
+// AsmInstruction is a single assembly instruction with the source line it came from.
+// Line is zero for instructions generated outside the submitted code.
+type AsmInstruction struct {
+	Offset   int    `json:"offset"`
+	Line     int    `json:"line"`
+	Op       string `json:"op"`
+	Operands string `json:"operands,omitempty"`
+}
+
+// buildGCFlags returns the -gcflags value for insight builds.
+func buildGCFlags(opts RunOptions) string {
+	if opts.Assembly {
+		return insightGCFlags + " " + assemblyGCFlag
+	}
+	return insightGCFlags
+}
+
+// parseInsight parses compiler output from an insight build.
+func parseInsight(output string, withAssembly bool) *Insight {
+	insight := &Insight{Annotations: []Annotation{}}
+	var current *AsmFunction
+	instructions := 0
+
+	for _, line := range strings.Split(output, "\n") {
+		if match := diagnosticRegex.FindStringSubmatch(line); match != nil {
+			insight.addDiagnostic(match)
+			continue
+		}
+
+		if !withAssembly {
+			continue
+		}
+
+		if match := asmHeaderRegex.FindStringSubmatch(line); match != nil {
+			size, _ := strconv.Atoi(match[2])
+			insight.Assembly = append(insight.Assembly, AsmFunction{Name: match[1], Size: size})
+			current = &insight.Assembly[len(insight.Assembly)-1]
+			continue
+		}
+
+		match := asmInstructionRegex.FindStringSubmatch(line)
+		if current == nil || match == nil || isAsmDirective(match[4]) {
+			continue
+		}
+		if instructions >= maxAsmInstructions {
+			insight.Truncated = true
+			continue
+		}
+		instructions++
+		current.Instructions = append(current.Instructions, newAsmInstruction(match))
+	}
+
+	return insight
+}
+
+// addDiagnostic records a diagnostic. Indented messages explain the previous decision.
+func (in *Insight) addDiagnostic(match []string) {
+	message := match[3]
+	if strings.HasPrefix(message, " ") && len(in.Annotations) > 0 {
+		last := &in.Annotations[len(in.Annotations)-1]
+		last.Details = append(last.Details, strings.TrimSpace(message))
+		return
+	}
+
+	line, _ := strconv.Atoi(match[1])
+	col, _ := strconv.Atoi(match[2])
+	in.Annotations = append(in.Annotations, Annotation{
+		Line:    line,
+		Column:  col,
+		Kind:    classifyDiagnostic(message),
+		Message: message,
+	})
+}
+
+// classifyDiagnostic maps a compiler message to an annotation kind.
+func classifyDiagnostic(message string) string {
+	switch {
+	case strings.HasPrefix(message, "can inline"):
+		return AnnotationCanInline
+	case strings.HasPrefix(message, "cannot inline"):
+		return AnnotationCannotInline
+	case strings.HasPrefix(message, "inlining call to"):
+		return AnnotationInlinedCall
+	case strings.HasPrefix(message, "moved to heap"):
+		return AnnotationMovedToHeap
+	case strings.HasPrefix(message, "leaking param"):
+		return AnnotationLeakingParam
+	case strings.Contains(message, "does not escape"):
+		return AnnotationNoEscape
+	case strings.Contains(message, "escapes to heap"):
+		return AnnotationEscapes
+	default:
+		return AnnotationInfo
+	}
+}
+
+// isAsmDirective reports whether an op is bookkeeping for the garbage collector
+// and stack maps rather than a machine instruction.
+func isAsmDirective(op string) bool {
+	return op == "PCDATA" || op == "FUNCDATA"
+}
+
+// newAsmInstruction builds an instruction from an asmInstructionRegex match.
+func newAsmInstruction(match []string) AsmInstruction {
+	offset, _ := strconv.Atoi(match[1])
+	instruction := AsmInstruction{
+		Offset:   offset,
+		Op:       match[4],
+		Operands: strings.TrimSpace(match[5]),
+	}
+	if strings.HasSuffix(match[2], "code.go") {
+		instruction.Line, _ = strconv.Atoi(match[3])
+	}
+	return instruction
+}
+
+// mapToSource rewrites line numbers from the prepared program to the submitted code,
+// dropping annotations that point into generated wrapper code.
+func (in *Insight) mapToSource(sm sourceMap) {
+	annotations := in.Annotations[:0]
+	for _, a := range in.Annotations {
+		line, col, ok := sm.toUser(a.Line, a.Column)
+		if !ok {
+			continue
+		}
+		a.Line, a.Column = line, col
+		annotations = append(annotations, a)
+	}
+	in.Annotations = annotations
+
+	for i := range in.Assembly {
+		for j := range in.Assembly[i].Instructions {
+			instruction := &in.Assembly[i].Instructions[j]
+			if line, _, ok := sm.toUser(instruction.Line, 0); ok {
+				instruction.Line = line
+			} else {
+				instruction.Line = 0
+			}
+		}
+	}
+}
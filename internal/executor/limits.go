@@ -0,0 +1,48 @@
+package executor
+
+import (
+	"cmp"
+	"time"
+)
+
+// Limits are the most resources a single run may request through RunOptions.
+// Requests above a limit are clamped to it rather than rejected. A limit that is not set
+// is the executor's default for that resource, so runs may only ask for more once the
+// operator raises the limit.
+type Limits struct {
+	Timeout    time.Duration
+	MemoryMB   int
+	CPUPercent int
+	MaxOutput  int
+}
+
+// defaultLimits sets the limits that were not configured to the executor's defaults.
+func (e *CodeExecutor) defaultLimits() {
+	e.limits.Timeout = cmp.Or(e.limits.Timeout, e.timeout)
+	e.limits.MemoryMB = cmp.Or(e.limits.MemoryMB, e.maxMemoryMB)
+	e.limits.CPUPercent = cmp.Or(e.limits.CPUPercent, e.maxCPUPercent)
+	e.limits.MaxOutput = cmp.Or(e.limits.MaxOutput, e.maxOutput)
+}
+
+// Limits returns the most resources a single run may request.
+func (e *CodeExecutor) Limits() Limits {
+	return e.limits
+}
+
+// resolveLimits fills in the executor defaults for resources a run did not request
+// and clamps every resource to the executor's limits, or the timeout to the run's own MaxTimeout.
+func (e *CodeExecutor) resolveLimits(opts RunOptions) RunOptions {
+	opts.Timeout = clampLimit(opts.Timeout, e.timeout, cmp.Or(opts.MaxTimeout, e.limits.Timeout))
+	opts.MemoryMB = clampLimit(opts.MemoryMB, e.maxMemoryMB, e.limits.MemoryMB)
+	opts.CPUPercent = clampLimit(opts.CPUPercent, e.maxCPUPercent, e.limits.CPUPercent)
+	opts.MaxOutput = clampLimit(opts.MaxOutput, e.maxOutput, e.limits.MaxOutput)
+	return opts
+}
+
+// clampLimit returns the requested value, or def when none was requested, capped at ceiling.
+func clampLimit[T int | time.Duration](requested, def, ceiling T) T {
+	if requested <= 0 {
+		requested = def
+	}
+	return min(requested, ceiling)
+}
@@ -0,0 +1,29 @@
+package executor_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jonesrussell/go-fundamentals-best-practices/internal/executor"
+)
+
+func TestResolveLimitsTimeout(t *testing.T) {
+	tests := []struct {
+		name string
+		opts executor.RunOptions
+		want time.Duration
+	}{
+		{name: "default", opts: executor.RunOptions{}, want: 10 * time.Second},
+		{name: "clamped to the limit", opts: executor.RunOptions{Timeout: time.Minute}, want: 10 * time.Second},
+		{name: "job", opts: executor.RunOptions{Timeout: time.Minute, MaxTimeout: 5 * time.Minute}, want: time.Minute},
+		{name: "clamped to the job's limit", opts: executor.RunOptions{Timeout: time.Hour, MaxTimeout: 5 * time.Minute}, want: 5 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := executor.ResolveLimits(tt.opts).Timeout; got != tt.want {
+				t.Errorf("timeout = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
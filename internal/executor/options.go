@@ -36,13 +36,45 @@ func WithMaxCPU(percent int) ExecutorOption {
 	}
 }
 
-// WithDockerImage sets the Docker image for Go compilation.
+// WithLimits sets the most resources a single run may request. Zero fields keep their defaults.
+func WithLimits(limits Limits) ExecutorOption {
+	return func(e *CodeExecutor) {
+		if limits.Timeout > 0 {
+			e.limits.Timeout = limits.Timeout
+		}
+		if limits.MemoryMB > 0 {
+			e.limits.MemoryMB = limits.MemoryMB
+		}
+		if limits.CPUPercent > 0 {
+			e.limits.CPUPercent = limits.CPUPercent
+		}
+		if limits.MaxOutput > 0 {
+			e.limits.MaxOutput = limits.MaxOutput
+		}
+	}
+}
+
+// WithDockerImage sets the Docker image for Go compilation with the default toolchain.
 func WithDockerImage(image string) ExecutorOption {
 	return func(e *CodeExecutor) {
 		e.compileImage = image
 	}
 }
 
+// WithToolchain registers (or replaces) a named toolchain and its compile image.
+func WithToolchain(name, image string) ExecutorOption {
+	return func(e *CodeExecutor) {
+		e.toolchains[name] = image
+	}
+}
+
+// WithDefaultToolchain selects the toolchain used when a request does not name one.
+func WithDefaultToolchain(name string) ExecutorOption {
+	return func(e *CodeExecutor) {
+		e.defaultToolchain = name
+	}
+}
+
 // WithLogger sets a custom logger.
 func WithLogger(logger *slog.Logger) ExecutorOption {
 	return func(e *CodeExecutor) {
@@ -0,0 +1,102 @@
+package executor
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Deterministic ("playground time") mode builds programs with the runtime's faketime tag.
+// The runtime then starts its clock at a fixed epoch, advances it instantly on sleeps,
+// and prefixes every write to stdout and stderr with a playback header:
+//
+//	0 0 'P' 'B' <8-byte virtual time in ns> <4-byte data length>
+//
+// The format is the one used by the official Go playground.
+const (
+	// faketimeBuildTag enables the runtime's virtual clock.
+	faketimeBuildTag = "faketime"
+	// deterministicGODEBUG seeds the top-level math/rand functions with a fixed value.
+	deterministicGODEBUG = "GODEBUG=randautoseed=0"
+	// playbackHeaderLen is the size of a playback header in bytes.
+	playbackHeaderLen = 4 + 8 + 4
+	// playbackTimeOffset is the offset of the timestamp within a playback header.
+	playbackTimeOffset = 4
+	// playbackLengthOffset is the offset of the data length within a playback header.
+	playbackLengthOffset = 12
+)
+
+// Output stream names.
+const (
+	streamStdout = "stdout"
+	streamStderr = "stderr"
+)
+
+// playbackMagic starts every playback header.
+var playbackMagic = []byte{0, 0, 'P', 'B'}
+
+// OutputEvent is a chunk of program output stamped with the virtual time it was written.
+type OutputEvent struct {
+	Stream  string        `json:"stream"`  // "stdout" or "stderr"
+	Message string        `json:"message"` // Output written by a single write call
+	Time    time.Time     `json:"time"`    // Virtual time of the write
+	Delay   time.Duration `json:"delay"`   // Virtual nanoseconds since the previous event
+}
+
+// parsePlayback splits faketime output into events. Bytes that are not framed by a
+// playback header (for example output truncated mid-record) become a single event
+// stamped with the time of the previous record.
+func parsePlayback(data []byte, stream string) []OutputEvent {
+	var events []OutputEvent
+	var last time.Time
+
+	for len(data) > 0 {
+		if len(data) < playbackHeaderLen || !bytes.HasPrefix(data, playbackMagic) {
+			events = append(events, OutputEvent{Stream: stream, Message: string(data), Time: last})
+			break
+		}
+
+		nanos := binary.BigEndian.Uint64(data[playbackTimeOffset:playbackLengthOffset])
+		size := int(binary.BigEndian.Uint32(data[playbackLengthOffset:playbackHeaderLen]))
+		data = data[playbackHeaderLen:]
+		size = min(size, len(data))
+
+		last = time.Unix(0, int64(nanos)).UTC()
+		events = append(events, OutputEvent{Stream: stream, Message: string(data[:size]), Time: last})
+		data = data[size:]
+	}
+
+	return events
+}
+
+// mergePlayback orders stdout and stderr events by virtual time and fills in delays.
+// The runtime guarantees timestamps strictly increase whenever the stream changes,
+// so sorting recovers the original interleaving.
+func mergePlayback(stdout, stderr []OutputEvent) []OutputEvent {
+	events := make([]OutputEvent, 0, len(stdout)+len(stderr))
+	events = append(events, stdout...)
+	events = append(events, stderr...)
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Time.Before(events[j].Time)
+	})
+
+	for i := range events {
+		if i > 0 {
+			events[i].Delay = events[i].Time.Sub(events[i-1].Time)
+		}
+	}
+
+	return events
+}
+
+// playbackOutput joins event messages into plain output.
+func playbackOutput(events []OutputEvent) string {
+	var builder strings.Builder
+	for _, event := range events {
+		builder.WriteString(event.Message)
+	}
+	return builder.String()
+}
@@ -0,0 +1,87 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// streamCapMultiplier bounds raw stream capture relative to the output limit,
+// leaving room for framing bytes that are stripped before truncation.
+const streamCapMultiplier = 4
+
+// cappedBuffer is a bytes.Buffer that silently discards writes beyond a limit.
+type cappedBuffer struct {
+	buf   bytes.Buffer
+	limit int
+}
+
+// Write implements io.Writer. It always reports the full length so copying continues.
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.buf.Len(); room > 0 {
+		b.buf.Write(p[:min(room, len(p))])
+	}
+	return len(p), nil
+}
+
+// Bytes returns the captured bytes.
+func (b *cappedBuffer) Bytes() []byte {
+	return b.buf.Bytes()
+}
+
+// attachedStreams captures a container's raw stdout and stderr as they are written.
+// Unlike container logs, attached streams are not re-encoded by the log driver,
+// so binary output survives intact.
+type attachedStreams struct {
+	resp   types.HijackedResponse
+	stdout cappedBuffer
+	stderr cappedBuffer
+	done   chan error
+}
+
+// attachStreams attaches to a created container. It must be called before the container starts.
+// Capture is bounded relative to maxOutput, the output limit of the run.
+func (de *dockerExecutor) attachStreams(ctx context.Context, containerID string, maxOutput int) (*attachedStreams, error) {
+	resp, err := de.client.ContainerAttach(ctx, containerID, container.AttachOptions{
+		Stream: true,
+		Stdout: true,
+		Stderr: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("attach container: %w", err)
+	}
+
+	limit := maxOutput * streamCapMultiplier
+	streams := &attachedStreams{
+		resp:   resp,
+		stdout: cappedBuffer{limit: limit},
+		stderr: cappedBuffer{limit: limit},
+		done:   make(chan error, 1),
+	}
+
+	go func() {
+		_, copyErr := stdcopy.StdCopy(&streams.stdout, &streams.stderr, resp.Reader)
+		streams.done <- copyErr
+	}()
+
+	return streams, nil
+}
+
+// wait blocks until the streams are drained, which happens once the container exits.
+func (s *attachedStreams) wait(ctx context.Context) error {
+	select {
+	case err := <-s.done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// close releases the attached connection.
+func (s *attachedStreams) close() {
+	s.resp.Close()
+}
@@ -0,0 +1,448 @@
+package executor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Program output is rendered the way a terminal would show it: ANSI escape sequences are
+// applied rather than printed, and carriage returns rewrite the current line, so progress
+// bars and spinners collapse to their final state.
+const (
+	// escapeByte starts every ANSI escape sequence.
+	escapeByte = '\x1b'
+	// maxTerminalColumns bounds how far cursor movement sequences can reach on one line.
+	// Written text is not bounded: lines run as long as the program writes them.
+	maxTerminalColumns = 1024
+	// defaultTTYColumns and defaultTTYRows size the pseudo-terminal when no width is requested.
+	defaultTTYColumns = 80
+	defaultTTYRows    = 24
+	// minTTYColumns and maxTTYColumns bound the requested terminal width.
+	minTTYColumns = 20
+	maxTTYColumns = 400
+	// ttyTerm is the terminal type advertised to programs running with a pseudo-terminal.
+	ttyTerm = "TERM=xterm-256color"
+)
+
+// SGR (Select Graphic Rendition) parameters that set colors; attributes are listed in sgrAttributes.
+const (
+	sgrForeground       = 30
+	sgrExtendedFg       = 38
+	sgrBackground       = 40
+	sgrExtendedBg       = 48
+	sgrBrightForeground = 90
+	sgrBrightBackground = 100
+	// sgrBasicColors is the number of colors in each basic range.
+	sgrBasicColors = 8
+	// sgrExtended256 and sgrExtendedRGB select the form of an extended color.
+	sgrExtended256 = 5
+	sgrExtendedRGB = 2
+	// rgbComponents is the number of arguments to a true color.
+	rgbComponents = 3
+)
+
+// The xterm 256-color palette: 16 basic colors, a 6x6x6 color cube, then 24 grays.
+const (
+	paletteCubeStart = 16
+	paletteGrayStart = 232
+	paletteMaxIndex  = 255
+	paletteCubeSize  = 6
+	paletteCubeStep  = 40
+	paletteCubeBase  = 55
+	paletteGrayStep  = 10
+	paletteGrayBase  = 8
+)
+
+// Erase modes of the erase in line (K) and erase in display (J) control sequences.
+const (
+	eraseToEnd    = 0
+	eraseToCursor = 1
+	eraseAll      = 2
+)
+
+// sgrAttributes maps SGR parameters to the attribute they set or clear.
+var sgrAttributes = map[int]func(*TextStyle){
+	0:  func(s *TextStyle) { *s = TextStyle{} },
+	1:  func(s *TextStyle) { s.Bold = true },
+	2:  func(s *TextStyle) { s.Dim = true },
+	3:  func(s *TextStyle) { s.Italic = true },
+	4:  func(s *TextStyle) { s.Underline = true },
+	7:  func(s *TextStyle) { s.Inverse = true },
+	9:  func(s *TextStyle) { s.Strikethrough = true },
+	22: func(s *TextStyle) { s.Bold, s.Dim = false, false },
+	23: func(s *TextStyle) { s.Italic = false },
+	24: func(s *TextStyle) { s.Underline = false },
+	27: func(s *TextStyle) { s.Inverse = false },
+	29: func(s *TextStyle) { s.Strikethrough = false },
+	39: func(s *TextStyle) { s.Foreground = "" },
+	49: func(s *TextStyle) { s.Background = "" },
+}
+
+// colorNames are the names of the 8 basic colors; bright variants carry a "bright-" prefix.
+var colorNames = [sgrBasicColors]string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+// TextStyle is the styling ANSI SGR sequences applied to a run of text.
+// Colors are basic color names such as "red" or "bright-red", or "#rrggbb" for 256-color and true color.
+type TextStyle struct {
+	Foreground    string `json:"fg,omitempty"`
+	Background    string `json:"bg,omitempty"`
+	Bold          bool   `json:"bold,omitempty"`
+	Dim           bool   `json:"dim,omitempty"`
+	Italic        bool   `json:"italic,omitempty"`
+	Underline     bool   `json:"underline,omitempty"`
+	Inverse       bool   `json:"inverse,omitempty"`
+	Strikethrough bool   `json:"strikethrough,omitempty"`
+}
+
+// StyledSpan is a run of text sharing one style.
+type StyledSpan struct {
+	Text string `json:"text"`
+	TextStyle
+}
+
+// StyledLine is one rendered line of output.
+type StyledLine struct {
+	Spans []StyledSpan `json:"spans"`
+}
+
+// cell is one character on the rendered screen.
+type cell struct {
+	r     rune
+	style TextStyle
+}
+
+// screen replays output onto an unbounded scrollback of lines.
+type screen struct {
+	lines  [][]cell
+	row    int
+	col    int
+	style  TextStyle
+	styled bool // Any text was written with a non-default style
+}
+
+// renderTerminal applies escape sequences, carriage returns and backspaces to output.
+// It returns the plain text as it would appear on a terminal and, when the output used
+// any styling, the styled lines. Output without control characters is returned unchanged.
+func renderTerminal(output string) (string, []StyledLine) {
+	if !strings.ContainsAny(output, "\x1b\r\b") {
+		return output, nil
+	}
+
+	s := &screen{lines: [][]cell{{}}}
+	s.write(output)
+
+	var text strings.Builder
+	for i, line := range s.lines {
+		if i > 0 {
+			text.WriteByte('\n')
+		}
+		for _, c := range line {
+			text.WriteRune(c.r)
+		}
+	}
+
+	if !s.styled {
+		return text.String(), nil
+	}
+	return text.String(), s.styledLines()
+}
+
+// write interprets output one character at a time.
+func (s *screen) write(output string) {
+	for i := 0; i < len(output); {
+		switch c := output[i]; c {
+		case escapeByte:
+			i += s.escape(output[i+1:]) + 1
+			continue
+		case '\n':
+			s.row++
+			s.col = 0
+			if s.row == len(s.lines) {
+				s.lines = append(s.lines, []cell{})
+			}
+		case '\r':
+			s.col = 0
+		case '\b':
+			s.col = max(s.col-1, 0)
+		case '\t':
+			s.put('\t')
+		default:
+			r, size := rune(c), 1
+			if c >= utf8.RuneSelf {
+				r, size = utf8.DecodeRuneInString(output[i:])
+			}
+			if r >= ' ' {
+				s.put(r)
+			}
+			i += size
+			continue
+		}
+		i++
+	}
+}
+
+// put writes a character at the cursor, overwriting what is there.
+func (s *screen) put(r rune) {
+	line := s.lines[s.row]
+	for len(line) < s.col {
+		line = append(line, cell{r: ' '})
+	}
+	c := cell{r: r, style: s.style}
+	if s.col < len(line) {
+		line[s.col] = c
+	} else {
+		line = append(line, c)
+	}
+	s.lines[s.row] = line
+	s.col++
+
+	if s.style != (TextStyle{}) {
+		s.styled = true
+	}
+}
+
+// escape handles the sequence following an escape byte and returns how many bytes it used.
+// Control sequences (CSI) move the cursor, erase and set styles; operating system commands
+// (OSC) such as window titles and hyperlinks are dropped; anything else is skipped.
+func (s *screen) escape(seq string) int {
+	if seq == "" {
+		return 0
+	}
+
+	switch seq[0] {
+	case '[':
+		n := csiLength(seq[1:])
+		if n == 0 {
+			return len(seq)
+		}
+		body := seq[1 : n+1]
+		s.control(body[:len(body)-1], body[len(body)-1])
+		return n + 1
+	case ']':
+		if end := strings.IndexAny(seq, "\a\x1b"); end >= 0 {
+			if seq[end] == escapeByte && end+1 < len(seq) {
+				return end + 2 // String terminator: ESC \
+			}
+			return end + 1
+		}
+		return len(seq)
+	default:
+		return 1
+	}
+}
+
+// csiLength returns the length of a control sequence body up to and including its final
+// byte, or 0 when the sequence is incomplete.
+func csiLength(seq string) int {
+	for i := range len(seq) {
+		if seq[i] >= '@' && seq[i] <= '~' {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// control applies a control sequence with its parameters and final byte.
+func (s *screen) control(params string, final byte) {
+	if strings.HasPrefix(params, "?") {
+		return // Private modes, e.g. hiding the cursor
+	}
+	args := csiParams(params)
+	count := max(arg(args, 0, 1), 1)
+
+	switch final {
+	case 'm':
+		s.style = applySGR(s.style, args)
+	case 'A', 'F':
+		s.row = max(s.row-count, 0)
+		if final == 'F' {
+			s.col = 0
+		}
+	case 'B', 'E':
+		s.row = min(s.row+count, len(s.lines)-1)
+		if final == 'E' {
+			s.col = 0
+		}
+	case 'C':
+		s.col = min(s.col+count, maxTerminalColumns)
+	case 'D':
+		s.col = max(s.col-count, 0)
+	case 'G':
+		s.col = min(count-1, maxTerminalColumns)
+	case 'K':
+		s.eraseLine(arg(args, 0, eraseToEnd))
+	case 'J':
+		if arg(args, 0, eraseToEnd) == eraseToEnd {
+			s.eraseLine(eraseToEnd)
+			s.lines = s.lines[:s.row+1]
+		}
+	default:
+	}
+}
+
+// eraseLine clears part of the cursor's line without moving the cursor.
+func (s *screen) eraseLine(mode int) {
+	line := s.lines[s.row]
+	switch mode {
+	case eraseToEnd:
+		s.lines[s.row] = line[:min(s.col, len(line))]
+	case eraseToCursor:
+		for i := range min(s.col+1, len(line)) {
+			line[i] = cell{r: ' '}
+		}
+	case eraseAll:
+		s.lines[s.row] = line[:0]
+	default:
+	}
+}
+
+// csiParams parses semicolon-separated parameters. Empty parameters are -1 so callers can apply defaults.
+func csiParams(params string) []int {
+	if params == "" {
+		return nil
+	}
+	var args []int
+	for field := range strings.SplitSeq(params, ";") {
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			n = -1
+		}
+		args = append(args, n)
+	}
+	return args
+}
+
+// arg returns parameter i, or def when it is missing or empty.
+func arg(args []int, i, def int) int {
+	if i >= len(args) || args[i] < 0 {
+		return def
+	}
+	return args[i]
+}
+
+// applySGR applies Select Graphic Rendition parameters to a style.
+func applySGR(style TextStyle, args []int) TextStyle {
+	if len(args) == 0 {
+		return TextStyle{}
+	}
+
+	for i := 0; i < len(args); i++ {
+		p := max(args[i], 0) // An empty parameter means reset
+		if set, ok := sgrAttributes[p]; ok {
+			set(&style)
+			continue
+		}
+
+		switch {
+		case p == sgrExtendedFg:
+			color, used := extendedColor(args[i+1:])
+			style.Foreground = color
+			i += used
+		case p == sgrExtendedBg:
+			color, used := extendedColor(args[i+1:])
+			style.Background = color
+			i += used
+		case p >= sgrForeground && p < sgrForeground+sgrBasicColors:
+			style.Foreground = colorNames[p-sgrForeground]
+		case p >= sgrBrightForeground && p < sgrBrightForeground+sgrBasicColors:
+			style.Foreground = brightColor(p - sgrBrightForeground)
+		case p >= sgrBackground && p < sgrBackground+sgrBasicColors:
+			style.Background = colorNames[p-sgrBackground]
+		case p >= sgrBrightBackground && p < sgrBrightBackground+sgrBasicColors:
+			style.Background = brightColor(p - sgrBrightBackground)
+		default:
+		}
+	}
+
+	return style
+}
+
+// brightColor names the bright variant of a basic color.
+func brightColor(index int) string {
+	return "bright-" + colorNames[index]
+}
+
+// extendedColor parses the arguments of a 256-color (5;n) or true color (2;r;g;b) parameter
+// and reports how many arguments it used.
+func extendedColor(args []int) (string, int) {
+	switch arg(args, 0, -1) {
+	case sgrExtended256:
+		if len(args) < 1+1 {
+			return "", len(args)
+		}
+		return paletteColor(args[1]), 1 + 1
+	case sgrExtendedRGB:
+		if len(args) < 1+rgbComponents {
+			return "", len(args)
+		}
+		return hexColor(args[1], args[2], args[3]), 1 + rgbComponents
+	default:
+		return "", len(args)
+	}
+}
+
+// paletteColor converts an xterm 256-color palette index to a color.
+func paletteColor(index int) string {
+	switch {
+	case index < 0 || index > paletteMaxIndex:
+		return ""
+	case index < sgrBasicColors:
+		return colorNames[index]
+	case index < paletteCubeStart:
+		return brightColor(index - sgrBasicColors)
+	case index < paletteGrayStart:
+		index -= paletteCubeStart
+		level := func(n int) int {
+			if n == 0 {
+				return 0
+			}
+			return paletteCubeBase + n*paletteCubeStep
+		}
+		return hexColor(
+			level(index/(paletteCubeSize*paletteCubeSize)),
+			level(index/paletteCubeSize%paletteCubeSize),
+			level(index%paletteCubeSize),
+		)
+	default:
+		gray := paletteGrayBase + (index-paletteGrayStart)*paletteGrayStep
+		return hexColor(gray, gray, gray)
+	}
+}
+
+// hexColor formats an RGB color as #rrggbb, clamping components to a byte.
+func hexColor(r, g, b int) string {
+	clamp := func(n int) int { return min(max(n, 0), paletteMaxIndex) }
+	return fmt.Sprintf("#%02x%02x%02x", clamp(r), clamp(g), clamp(b))
+}
+
+// styledLines groups each line's characters into spans of the same style.
+func (s *screen) styledLines() []StyledLine {
+	lines := make([]StyledLine, len(s.lines))
+	for i, line := range s.lines {
+		spans := []StyledSpan{}
+		var text strings.Builder
+		for j, c := range line {
+			if j > 0 && c.style != line[j-1].style {
+				spans = append(spans, StyledSpan{Text: text.String(), TextStyle: line[j-1].style})
+				text.Reset()
+			}
+			text.WriteRune(c.r)
+		}
+		if len(line) > 0 {
+			spans = append(spans, StyledSpan{Text: text.String(), TextStyle: line[len(line)-1].style})
+		}
+		lines[i] = StyledLine{Spans: spans}
+	}
+	return lines
+}
+
+// ttySize returns the pseudo-terminal size for a requested width, clamped to sensible bounds.
+func ttySize(columns int) [2]uint {
+	if columns <= 0 {
+		columns = defaultTTYColumns
+	}
+	columns = min(max(columns, minTTYColumns), maxTTYColumns)
+	return [2]uint{defaultTTYRows, uint(columns)}
+}
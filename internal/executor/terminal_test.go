@@ -0,0 +1,35 @@
+package executor_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jonesrussell/go-fundamentals-best-practices/internal/executor"
+)
+
+// TestRenderTerminalLongLine checks that text past the cursor movement bound is kept, as on a
+// pseudo-terminal, where every line ends in a carriage return.
+func TestRenderTerminalLongLine(t *testing.T) {
+	long := strings.Repeat("0123456789", 200)
+	output := long + "\r\n\x1b[31mred\x1b[0m done\r\n"
+
+	text, styled := executor.RenderTerminal(output)
+
+	want := long + "\nred done\n"
+	if text != want {
+		t.Fatalf("text has %d characters, want %d; first line %d characters, want %d",
+			len(text), len(want), len(strings.Split(text, "\n")[0]), len(long))
+	}
+
+	if len(styled) != 3 {
+		t.Fatalf("got %d styled lines, want 3", len(styled))
+	}
+	if got := styled[0].Spans; len(got) != 1 || got[0].Text != long {
+		t.Errorf("long line spans = %d, want one span of the whole line", len(got))
+	}
+	spans := styled[1].Spans
+	if len(spans) != 2 || spans[0].Text != "red" || spans[0].Foreground != "red" ||
+		spans[1].Text != " done" || spans[1].Foreground != "" {
+		t.Errorf("colored line spans = %+v, want red \"red\" then plain \" done\"", spans)
+	}
+}
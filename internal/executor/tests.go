@@ -0,0 +1,41 @@
+package executor
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// Test runs build the code with a test file using go test -c and run the test binary in place
+// of the program.
+const (
+	// testFile is the test source compiled alongside code.go.
+	testFile = "code_test.go"
+)
+
+// ValidateTests checks that the other options of a test run can be used with tests. Trace and
+// visualization record the program's main, which a test binary never runs.
+func (o RunOptions) ValidateTests() error {
+	if o.Tests == "" {
+		return nil
+	}
+	if o.Trace {
+		return fmt.Errorf("%w: trace", ErrTestsUnsupported)
+	}
+	if o.Visualize {
+		return fmt.Errorf("%w: visualize", ErrTestsUnsupported)
+	}
+	return nil
+}
+
+// testArgs returns the arguments of the binary: none for a program, and for a test binary
+// verbose output and, in coverage mode, a text profile in the coverage directory.
+func testArgs(opts RunOptions) string {
+	if opts.Tests == "" {
+		return ""
+	}
+	args := " -test.v"
+	if opts.Coverage {
+		args += " -test.coverprofile=" + filepath.Join(coverContainerDir, coverProfileFile)
+	}
+	return args
+}
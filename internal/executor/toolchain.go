@@ -0,0 +1,106 @@
+package executor
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// defaultToolchain is the toolchain used when a request does not select one.
+const defaultToolchain = "1.25"
+
+// defaultToolchains maps toolchain names to compile images.
+var defaultToolchains = map[string]string{
+	"1.21": "golang:1.21-alpine",
+	"1.22": "golang:1.22-alpine",
+	"1.25": defaultCompileImage,
+}
+
+// Toolchain describes a Go toolchain available for compilation.
+type Toolchain struct {
+	Name    string `json:"name"`
+	Image   string `json:"image"`
+	Default bool   `json:"default,omitempty"`
+}
+
+// Toolchains returns the available toolchains, oldest first.
+func (e *CodeExecutor) Toolchains() []Toolchain {
+	toolchains := make([]Toolchain, 0, len(e.toolchains))
+	for name, image := range e.toolchains {
+		toolchains = append(toolchains, Toolchain{
+			Name:    name,
+			Image:   image,
+			Default: name == e.defaultToolchain,
+		})
+	}
+
+	sort.Slice(toolchains, func(i, j int) bool {
+		return compareVersions(toolchains[i].Name, toolchains[j].Name) < 0
+	})
+
+	return toolchains
+}
+
+// compileImages returns the compile image of every toolchain, the default toolchain's first.
+// Toolchains sharing an image list it once.
+func (e *CodeExecutor) compileImages() []string {
+	images := []string{e.compileImage}
+	for _, toolchain := range e.Toolchains() {
+		if !slices.Contains(images, toolchain.Image) {
+			images = append(images, toolchain.Image)
+		}
+	}
+	return images
+}
+
+// ValidateToolchain reports whether a toolchain name can be used for execution.
+// An empty name is valid and selects the default toolchain.
+func (e *CodeExecutor) ValidateToolchain(name string) error {
+	_, _, err := e.resolveToolchain(name)
+	return err
+}
+
+// resolveToolchain returns the name and compile image for a requested toolchain.
+// An empty name selects the default toolchain.
+func (e *CodeExecutor) resolveToolchain(name string) (resolved, image string, err error) {
+	if name == "" {
+		name = e.defaultToolchain
+	}
+
+	image, ok := e.toolchains[name]
+	if !ok {
+		return "", "", fmt.Errorf("%w: %s", ErrUnknownToolchain, name)
+	}
+
+	return name, image, nil
+}
+
+// compareVersions compares dotted version strings numerically.
+// Non-numeric components compare lexically.
+func compareVersions(a, b string) int {
+	partsA := strings.Split(a, ".")
+	partsB := strings.Split(b, ".")
+
+	for i := range max(len(partsA), len(partsB)) {
+		var pa, pb string
+		if i < len(partsA) {
+			pa = partsA[i]
+		}
+		if i < len(partsB) {
+			pb = partsB[i]
+		}
+
+		na, errA := strconv.Atoi(pa)
+		nb, errB := strconv.Atoi(pb)
+		switch {
+		case errA == nil && errB == nil && na != nb:
+			return na - nb
+		case (errA != nil || errB != nil) && pa != pb:
+			return strings.Compare(pa, pb)
+		}
+	}
+
+	return 0
+}
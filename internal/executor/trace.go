@@ -0,0 +1,393 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"strings"
+
+	"golang.org/x/exp/trace"
+)
+
+// Trace builds run the user's main under runtime/trace.
+const (
+	// traceUserMain is what the user's main function is renamed to so the trace harness can wrap it.
+	traceUserMain = "tutorialUserMain"
+	// traceMainFile is the harness source compiled alongside code.go.
+	traceMainFile = "trace_main.go"
+	// traceContainerPath is where the harness writes the trace inside the run container.
+	traceContainerPath = "/tmp/trace.out"
+	// maxTraceBytes bounds the trace file copied out of the container.
+	maxTraceBytes = 16 * bytesPerKB * bytesPerKB
+	// maxTraceSegments bounds the number of goroutine segments returned to clients.
+	maxTraceSegments = 10000
+	// minTraceToolchain is the oldest toolchain whose traces golang.org/x/exp/trace can read.
+	minTraceToolchain = "1.22"
+)
+
+// traceMainSource starts tracing, runs the user's main and flushes the trace when it returns or panics.
+// Its imports are renamed because they share the package block with the user's declarations,
+// which may include an os or trace of their own.
+const traceMainSource = `package main
+
+import (
+	tutorialTraceOS "os"
+	tutorialTrace "runtime/trace"
+)
+
+func main() {
+	f, err := tutorialTraceOS.Create("` + traceContainerPath + `")
+	if err != nil {
+		panic(err)
+	}
+	if err := tutorialTrace.Start(f); err != nil {
+		panic(err)
+	}
+	defer func() {
+		tutorialTrace.Stop()
+		_ = f.Close()
+	}()
+
+	` + traceUserMain + `()
+}
+`
+
+// Goroutine states reported in a timeline.
+const (
+	GoroutineRunning  = "running"
+	GoroutineRunnable = "runnable"
+	GoroutineWaiting  = "waiting"
+	GoroutineSyscall  = "syscall"
+)
+
+// Blocking kinds for waiting segments.
+const (
+	BlockChanSend    = "chan-send"
+	BlockChanReceive = "chan-receive"
+	BlockSelect      = "select"
+	BlockMutex       = "mutex"
+	BlockWaitGroup   = "waitgroup"
+	BlockSync        = "sync"
+	BlockSleep       = "sleep"
+	BlockOther       = "other"
+)
+
+// GC phases reported in a timeline.
+const (
+	GCStopTheWorld = "stop-the-world"
+	GCMark         = "mark"
+)
+
+// Timeline is a compact view of a runtime trace for drawing goroutine swimlanes.
+// All times are nanoseconds since the start of the trace.
+type Timeline struct {
+	DurationNs int64           `json:"durationNs"`
+	Goroutines []GoroutineLane `json:"goroutines"`
+	GC         []GCPhase       `json:"gc,omitempty"`
+	Truncated  bool            `json:"truncated,omitempty"` // Segments were cut at the limit
+}
+
+// GoroutineLane is the lifetime of one user goroutine.
+type GoroutineLane struct {
+	ID       int64          `json:"id"`
+	Function string         `json:"function,omitempty"` // Start function, e.g. "main.main.func1"
+	StartNs  int64          `json:"startNs"`
+	EndNs    int64          `json:"endNs"`
+	Segments []TraceSegment `json:"segments"`
+}
+
+// TraceSegment is a span of time a goroutine spent in one state.
+type TraceSegment struct {
+	State   string `json:"state"`
+	Block   string `json:"block,omitempty"`  // What a waiting goroutine was blocked on
+	Reason  string `json:"reason,omitempty"` // Runtime's description, e.g. "chan receive"
+	StartNs int64  `json:"startNs"`
+	EndNs   int64  `json:"endNs"`
+}
+
+// GCPhase is a garbage collector phase: a stop-the-world pause or concurrent marking.
+type GCPhase struct {
+	Kind    string `json:"kind"`
+	Name    string `json:"name"`
+	StartNs int64  `json:"startNs"`
+	EndNs   int64  `json:"endNs"`
+}
+
+// instrumentTrace renames the program's main function so the trace harness can call it.
+// Only the identifier changes, so line numbers stay the same. It reports false when the
+// code has no main function to wrap, in which case the build reports the problem.
+func instrumentTrace(code string) (string, bool) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "code.go", code, parser.SkipObjectResolution)
+	if err != nil || file.Name.Name != "main" {
+		return code, false
+	}
+
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil || fn.Name.Name != "main" {
+			continue
+		}
+		offset := fset.Position(fn.Name.Pos()).Offset
+		return code[:offset] + traceUserMain + code[offset+len(fn.Name.Name):], true
+	}
+
+	return code, false
+}
+
+// timelineBuilder accumulates goroutine and GC activity while reading a trace.
+type timelineBuilder struct {
+	timeline Timeline
+	start    trace.Time
+	started  bool
+	lanes    map[trace.GoID]*laneState
+	gc       map[string]int // open GC phase name -> index
+	segments int
+}
+
+// laneState tracks the open segment of a goroutine.
+type laneState struct {
+	index   int // Index into Timeline.Goroutines; -1 for runtime goroutines
+	segment TraceSegment
+	open    bool
+}
+
+// ValidateTrace reports whether trace mode can be used with a toolchain. An empty name
+// selects the default toolchain.
+func (e *CodeExecutor) ValidateTrace(toolchain string) error {
+	name, _, err := e.resolveToolchain(toolchain)
+	if err != nil {
+		return err
+	}
+	return checkTraceToolchain(name)
+}
+
+// checkTraceToolchain rejects toolchains older than minTraceToolchain, whose trace format
+// the trace reader does not support.
+func checkTraceToolchain(name string) error {
+	if compareVersions(name, minTraceToolchain) < 0 {
+		return fmt.Errorf("%w: toolchain %s, trace mode needs Go %s or later", ErrTraceUnsupported, name, minTraceToolchain)
+	}
+	return nil
+}
+
+// parseTrace reads a runtime trace into a timeline of user goroutines and GC phases.
+func parseTrace(r io.Reader) (*Timeline, error) {
+	reader, err := trace.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("read trace: %w", err)
+	}
+
+	b := &timelineBuilder{
+		timeline: Timeline{Goroutines: []GoroutineLane{}},
+		lanes:    map[trace.GoID]*laneState{},
+		gc:       map[string]int{},
+	}
+
+	for {
+		ev, readErr := reader.ReadEvent()
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+		if readErr != nil {
+			return nil, fmt.Errorf("read trace event: %w", readErr)
+		}
+		b.add(ev)
+	}
+
+	return b.finish(), nil
+}
+
+// add records a single trace event.
+func (b *timelineBuilder) add(ev trace.Event) {
+	if !b.started {
+		b.start, b.started = ev.Time(), true
+	}
+	now := int64(ev.Time() - b.start)
+	b.timeline.DurationNs = now
+
+	switch ev.Kind() {
+	case trace.EventStateTransition:
+		st := ev.StateTransition()
+		if st.Resource.Kind == trace.ResourceGoroutine {
+			b.transition(st, now)
+		}
+	case trace.EventRangeBegin, trace.EventRangeActive:
+		if kind := gcPhaseKind(ev.Range().Name); kind != "" {
+			b.gc[ev.Range().Name] = len(b.timeline.GC)
+			b.timeline.GC = append(b.timeline.GC, GCPhase{Kind: kind, Name: ev.Range().Name, StartNs: now, EndNs: -1})
+		}
+	case trace.EventRangeEnd:
+		if index, ok := b.gc[ev.Range().Name]; ok {
+			b.timeline.GC[index].EndNs = now
+			delete(b.gc, ev.Range().Name)
+		}
+	default:
+	}
+}
+
+// transition closes a goroutine's current segment and opens the next one.
+func (b *timelineBuilder) transition(st trace.StateTransition, now int64) {
+	id := st.Resource.Goroutine()
+	from, to := st.Goroutine()
+
+	lane, ok := b.lanes[id]
+	if !ok {
+		lane = b.newLane(id, from, st.Stack, now)
+		b.lanes[id] = lane
+	}
+	if lane.index < 0 {
+		return
+	}
+
+	b.closeSegment(lane, now)
+	if to == trace.GoNotExist {
+		b.timeline.Goroutines[lane.index].EndNs = now
+		return
+	}
+
+	state := goroutineState(to)
+	if state == "" {
+		return
+	}
+	lane.segment = TraceSegment{State: state, StartNs: now}
+	if to == trace.GoWaiting {
+		lane.segment.Reason = st.Reason
+		lane.segment.Block = classifyBlock(st.Reason, firstFrame(st.Stack))
+	}
+	lane.open = true
+}
+
+// newLane starts tracking a goroutine. Goroutines started by the runtime get no lane.
+func (b *timelineBuilder) newLane(id trace.GoID, from trace.GoState, stack trace.Stack, now int64) *laneState {
+	var function string
+	switch {
+	case from == trace.GoNotExist:
+		function = userFunction(firstFrame(stack))
+	case id == 1:
+		// The main goroutine was already running when tracing started
+		function = "main.main"
+	}
+
+	if function == "" || strings.HasPrefix(function, "runtime") {
+		return &laneState{index: -1}
+	}
+
+	start := now
+	if from != trace.GoNotExist {
+		start = 0
+	}
+	b.timeline.Goroutines = append(b.timeline.Goroutines, GoroutineLane{
+		ID:       int64(id),
+		Function: function,
+		StartNs:  start,
+		EndNs:    -1,
+		Segments: []TraceSegment{},
+	})
+	return &laneState{index: len(b.timeline.Goroutines) - 1}
+}
+
+// closeSegment ends a goroutine's open segment, dropping it once the segment limit is reached.
+func (b *timelineBuilder) closeSegment(lane *laneState, now int64) {
+	if !lane.open {
+		return
+	}
+	lane.open = false
+
+	if b.segments >= maxTraceSegments {
+		b.timeline.Truncated = true
+		return
+	}
+	b.segments++
+
+	lane.segment.EndNs = now
+	goroutine := &b.timeline.Goroutines[lane.index]
+	goroutine.Segments = append(goroutine.Segments, lane.segment)
+}
+
+// finish closes everything still open at the end of the trace.
+func (b *timelineBuilder) finish() *Timeline {
+	end := b.timeline.DurationNs
+	for _, lane := range b.lanes {
+		if lane.index < 0 {
+			continue
+		}
+		b.closeSegment(lane, end)
+		if b.timeline.Goroutines[lane.index].EndNs < 0 {
+			b.timeline.Goroutines[lane.index].EndNs = end
+		}
+	}
+	for i := range b.timeline.GC {
+		if b.timeline.GC[i].EndNs < 0 {
+			b.timeline.GC[i].EndNs = end
+		}
+	}
+	return &b.timeline
+}
+
+// goroutineState maps a trace state to a timeline state. Unknown states map to "".
+func goroutineState(state trace.GoState) string {
+	switch state {
+	case trace.GoRunning:
+		return GoroutineRunning
+	case trace.GoRunnable:
+		return GoroutineRunnable
+	case trace.GoWaiting:
+		return GoroutineWaiting
+	case trace.GoSyscall:
+		return GoroutineSyscall
+	default:
+		return ""
+	}
+}
+
+// classifyBlock maps a blocking reason, and the function the goroutine blocked in, to a blocking kind.
+// The runtime reports every sync package wait as "sync", so the frame tells mutexes from wait groups.
+func classifyBlock(reason, frame string) string {
+	switch {
+	case reason == "chan send":
+		return BlockChanSend
+	case reason == "chan receive":
+		return BlockChanReceive
+	case reason == "select":
+		return BlockSelect
+	case reason == "sleep":
+		return BlockSleep
+	case strings.Contains(frame, "Mutex"):
+		return BlockMutex
+	case strings.Contains(frame, "WaitGroup"):
+		return BlockWaitGroup
+	case strings.HasPrefix(reason, "sync"):
+		return BlockSync
+	default:
+		return BlockOther
+	}
+}
+
+// gcPhaseKind reports the timeline kind of a runtime range, or "" for ranges that are not shown.
+func gcPhaseKind(name string) string {
+	switch {
+	case strings.HasPrefix(name, "stop-the-world"):
+		return GCStopTheWorld
+	case name == "GC concurrent mark phase":
+		return GCMark
+	default:
+		return ""
+	}
+}
+
+// firstFrame returns the innermost function of a stack, or "" when the stack is empty.
+func firstFrame(stack trace.Stack) string {
+	for frame := range stack.Frames() {
+		return frame.Func
+	}
+	return ""
+}
+
+// userFunction undoes the main renaming so functions appear as the user wrote them.
+func userFunction(function string) string {
+	return strings.ReplaceAll(function, "main."+traceUserMain, "main.main")
+}
@@ -0,0 +1,397 @@
+package executor
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Visualization builds call a recorder after each statement with the local variables in scope.
+const (
+	// vizStepFunc is the recorder function inserted after each statement.
+	vizStepFunc = "tutorialVizStep"
+	// vizRecorderFile is the recorder source compiled alongside code.go.
+	vizRecorderFile = "viz_recorder.go"
+	// vizPlainDir holds the uninstrumented code.go, compiled first to report its compile errors.
+	vizPlainDir = "plain"
+	// vizContainerPath is where the recorder writes steps, one JSON object per line.
+	vizContainerPath = "/tmp/viz.jsonl"
+	// maxVizSteps bounds the number of steps the recorder writes.
+	maxVizSteps = 500
+	// maxVizBytes bounds the step file copied out of the container.
+	maxVizBytes = 8 * bytesPerKB * bytesPerKB
+)
+
+// Reasons recording stopped before the program finished.
+const (
+	// VizStoppedStepLimit means the program ran more steps than are recorded.
+	VizStoppedStepLimit = "step-limit"
+	// VizStoppedGoroutines means the program started a goroutine. Reading variables that
+	// other goroutines may be writing could crash the program, so recording stops.
+	VizStoppedGoroutines = "goroutines"
+)
+
+// Visualization is the step-by-step state of a program's local variables.
+type Visualization struct {
+	Steps   []VizStep `json:"steps"`
+	Stopped string    `json:"stopped,omitempty"` // Why recording stopped early, if it did
+}
+
+// VizStep is the state of the local variables after a statement ran.
+type VizStep struct {
+	Line      int           `json:"line"`
+	Function  string        `json:"function"`
+	Variables []VizVariable `json:"variables"`
+}
+
+// VizVariable is a named local variable.
+type VizVariable struct {
+	Name  string   `json:"name"`
+	Value VizValue `json:"value"`
+}
+
+// VizValue describes a recorded value. Kind is the reflect kind, e.g. "int", "ptr" or "slice".
+// Pointers carry their address and target; slices their length, capacity, backing array
+// address and elements up to the capacity.
+type VizValue struct {
+	Kind     string       `json:"kind"`
+	Type     string       `json:"type"`
+	Value    string       `json:"value,omitempty"`   // Formatted scalar, or "nil"
+	Address  string       `json:"address,omitempty"` // Pointer target or slice backing array
+	Target   *VizValue    `json:"target,omitempty"`
+	Len      *int         `json:"len,omitempty"`
+	Cap      *int         `json:"cap,omitempty"`
+	Elements []VizValue   `json:"elements,omitempty"`
+	Fields   []VizField   `json:"fields,omitempty"`
+	Entries  []VizMapItem `json:"entries,omitempty"`
+	Elided   bool         `json:"elided,omitempty"` // Nested too deep or too many elements to record
+}
+
+// VizField is a struct field.
+type VizField struct {
+	Name  string   `json:"name"`
+	Value VizValue `json:"value"`
+}
+
+// VizMapItem is a map entry.
+type VizMapItem struct {
+	Key   VizValue `json:"key"`
+	Value VizValue `json:"value"`
+}
+
+// vizRecord is a line of the step file: a step, or the marker written when recording stops.
+type vizRecord struct {
+	VizStep
+
+	Stopped string `json:"stopped"`
+}
+
+// vizInsertion is recorder code to insert at a byte offset of the source.
+type vizInsertion struct {
+	offset int
+	text   string
+}
+
+// vizInstrumenter inserts recorder calls into a parsed file.
+type vizInstrumenter struct {
+	fset       *token.FileSet
+	insertions []vizInsertion
+}
+
+// instrumentVisualization inserts a recorder call after each statement of every function.
+// Calls are inserted on the line of the statement they follow, so line numbers are unchanged.
+// It reports false when the code does not parse, in which case the build reports the problem.
+func instrumentVisualization(code string) (string, bool) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "code.go", code, parser.SkipObjectResolution)
+	if err != nil || file.Name.Name != "main" {
+		return code, false
+	}
+
+	vi := &vizInstrumenter{fset: fset}
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+		scope := fieldNames(nil, fn.Recv)
+		scope = fieldNames(scope, fn.Type.Params)
+		scope = fieldNames(scope, fn.Type.Results)
+		vi.block(fn.Body.List, scope, fn.Name.Name)
+	}
+
+	// Insert from the end so earlier offsets stay valid
+	sort.SliceStable(vi.insertions, func(i, j int) bool {
+		return vi.insertions[i].offset > vi.insertions[j].offset
+	})
+	for _, ins := range vi.insertions {
+		code = code[:ins.offset] + ins.text + code[ins.offset:]
+	}
+
+	return code, true
+}
+
+// block instruments a statement list. Names declared by a statement are in scope after it.
+func (vi *vizInstrumenter) block(list []ast.Stmt, scope []string, function string) {
+	for _, stmt := range list {
+		vi.nested(stmt, scope, function)
+		scope = declaredNames(scope, stmt)
+		if !isTerminating(stmt) {
+			vi.record(stmt, scope, function)
+		}
+	}
+}
+
+// nested instruments the statement lists and function literals inside a statement.
+func (vi *vizInstrumenter) nested(stmt ast.Stmt, scope []string, function string) {
+	switch s := stmt.(type) {
+	case *ast.BlockStmt:
+		vi.block(s.List, scope, function)
+	case *ast.LabeledStmt:
+		vi.nested(s.Stmt, scope, function)
+	case *ast.IfStmt:
+		vi.funcLits(s.Cond, scope, function)
+		inner := declaredNames(scope, s.Init)
+		vi.block(s.Body.List, inner, function)
+		if s.Else != nil {
+			vi.nested(s.Else, inner, function)
+		}
+	case *ast.ForStmt:
+		vi.block(s.Body.List, declaredNames(scope, s.Init), function)
+	case *ast.RangeStmt:
+		vi.funcLits(s.X, scope, function)
+		inner := scope
+		if s.Tok == token.DEFINE {
+			inner = identNames(scope, s.Key, s.Value)
+		}
+		vi.block(s.Body.List, inner, function)
+	case *ast.SwitchStmt:
+		vi.clauses(s.Body, declaredNames(scope, s.Init), function)
+	case *ast.TypeSwitchStmt:
+		inner := declaredNames(scope, s.Init)
+		if assign, ok := s.Assign.(*ast.AssignStmt); ok {
+			inner = declaredNames(inner, assign)
+		}
+		vi.clauses(s.Body, inner, function)
+	case *ast.SelectStmt:
+		vi.clauses(s.Body, scope, function)
+	default:
+		vi.funcLits(stmt, scope, function)
+	}
+}
+
+// clauses instruments the bodies of switch and select clauses.
+func (vi *vizInstrumenter) clauses(body *ast.BlockStmt, scope []string, function string) {
+	for _, clause := range body.List {
+		switch c := clause.(type) {
+		case *ast.CaseClause:
+			vi.block(c.Body, scope, function)
+		case *ast.CommClause:
+			vi.block(c.Body, declaredNames(scope, c.Comm), function)
+		}
+	}
+}
+
+// funcLits instruments function literals found in a node. Their bodies see the enclosing scope.
+func (vi *vizInstrumenter) funcLits(node ast.Node, scope []string, function string) {
+	if node == nil {
+		return
+	}
+	ast.Inspect(node, func(n ast.Node) bool {
+		lit, ok := n.(*ast.FuncLit)
+		if !ok {
+			return true
+		}
+		inner := fieldNames(scope, lit.Type.Params)
+		inner = fieldNames(inner, lit.Type.Results)
+		vi.block(lit.Body.List, inner, function+".func")
+		return false
+	})
+}
+
+// record inserts a recorder call after a statement, passing each variable in scope.
+func (vi *vizInstrumenter) record(stmt ast.Stmt, scope []string, function string) {
+	pos := vi.fset.Position(stmt.Pos())
+
+	var call strings.Builder
+	fmt.Fprintf(&call, "; %s(%d, %s", vizStepFunc, pos.Line, strconv.Quote(function))
+	for _, name := range visibleNames(scope) {
+		fmt.Fprintf(&call, ", %s, %s", strconv.Quote(name), name)
+	}
+	call.WriteString(")")
+
+	vi.insertions = append(vi.insertions, vizInsertion{
+		offset: vi.fset.Position(stmt.End()).Offset,
+		text:   call.String(),
+	})
+}
+
+// declaredNames returns scope extended with the variables a statement declares.
+func declaredNames(scope []string, stmt ast.Stmt) []string {
+	switch s := stmt.(type) {
+	case *ast.AssignStmt:
+		if s.Tok == token.DEFINE {
+			return identNames(scope, s.Lhs...)
+		}
+	case *ast.DeclStmt:
+		gen, ok := s.Decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR {
+			return scope
+		}
+		for _, spec := range gen.Specs {
+			if vs, isValue := spec.(*ast.ValueSpec); isValue {
+				for _, name := range vs.Names {
+					scope = appendName(scope, name.Name)
+				}
+			}
+		}
+	}
+	return scope
+}
+
+// identNames returns scope extended with the identifiers among exprs.
+func identNames(scope []string, exprs ...ast.Expr) []string {
+	for _, expr := range exprs {
+		if ident, ok := expr.(*ast.Ident); ok {
+			scope = appendName(scope, ident.Name)
+		}
+	}
+	return scope
+}
+
+// fieldNames returns scope extended with the names in a parameter or result list.
+func fieldNames(scope []string, fields *ast.FieldList) []string {
+	if fields == nil {
+		return scope
+	}
+	for _, field := range fields.List {
+		for _, name := range field.Names {
+			scope = appendName(scope, name.Name)
+		}
+	}
+	return scope
+}
+
+// appendName adds a name to a copy of scope, so sibling blocks do not share declarations.
+func appendName(scope []string, name string) []string {
+	if name == "_" {
+		return scope
+	}
+	return append(scope[:len(scope):len(scope)], name)
+}
+
+// visibleNames returns the names in scope, keeping only the innermost of shadowed names.
+func visibleNames(scope []string) []string {
+	seen := map[string]bool{}
+	names := make([]string, 0, len(scope))
+	for i := len(scope) - 1; i >= 0; i-- {
+		if seen[scope[i]] {
+			continue
+		}
+		seen[scope[i]] = true
+		names = append(names, scope[i])
+	}
+	// Restore declaration order
+	for i, j := 0, len(names)-1; i < j; i, j = i+1, j-1 {
+		names[i], names[j] = names[j], names[i]
+	}
+	return names
+}
+
+// isTerminating reports whether a statement may end its function, following the spec's
+// terminating statements loosely. Nothing is recorded after these, both because it would
+// never run and because a trailing call would break the function's "missing return" check.
+func isTerminating(stmt ast.Stmt) bool {
+	switch s := stmt.(type) {
+	case *ast.ReturnStmt, *ast.BranchStmt:
+		return true
+	case *ast.ExprStmt:
+		call, ok := s.X.(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+		ident, ok := call.Fun.(*ast.Ident)
+		return ok && ident.Name == "panic"
+	case *ast.BlockStmt:
+		return endsTerminating(s.List)
+	case *ast.LabeledStmt:
+		return isTerminating(s.Stmt)
+	case *ast.IfStmt:
+		return s.Else != nil && endsTerminating(s.Body.List) && isTerminating(s.Else)
+	case *ast.ForStmt:
+		return s.Cond == nil
+	case *ast.SwitchStmt:
+		return clausesTerminate(s.Body)
+	case *ast.TypeSwitchStmt:
+		return clausesTerminate(s.Body)
+	case *ast.SelectStmt:
+		return clausesTerminate(s.Body)
+	default:
+		return false
+	}
+}
+
+// endsTerminating reports whether a statement list ends in a terminating statement.
+func endsTerminating(list []ast.Stmt) bool {
+	return len(list) > 0 && isTerminating(list[len(list)-1])
+}
+
+// clausesTerminate reports whether every clause of a switch or select ends in a terminating statement.
+func clausesTerminate(body *ast.BlockStmt) bool {
+	for _, clause := range body.List {
+		var list []ast.Stmt
+		switch c := clause.(type) {
+		case *ast.CaseClause:
+			list = c.Body
+		case *ast.CommClause:
+			list = c.Body
+		}
+		if !endsTerminating(list) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseVisualization reads the recorder's step file.
+func parseVisualization(data []byte) (*Visualization, error) {
+	viz := &Visualization{Steps: []VizStep{}}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, maxVizBytes)
+	for scanner.Scan() {
+		var record vizRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("decode step: %w", err)
+		}
+		if record.Stopped != "" {
+			viz.Stopped = record.Stopped
+			continue
+		}
+		viz.Steps = append(viz.Steps, record.VizStep)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read steps: %w", err)
+	}
+	return viz, nil
+}
+
+// mapToSource rewrites step lines from the prepared program to the submitted code.
+func (v *Visualization) mapToSource(sm sourceMap) {
+	steps := v.Steps[:0]
+	for _, step := range v.Steps {
+		line, _, ok := sm.toUser(step.Line, 0)
+		if !ok {
+			continue
+		}
+		step.Line = line
+		steps = append(steps, step)
+	}
+	v.Steps = steps
+}
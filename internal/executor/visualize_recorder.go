@@ -0,0 +1,46 @@
+package executor
+
+import (
+	_ "embed"
+	"strings"
+	"text/template"
+)
+
+// Recorder limits, keeping each step small enough to send to the browser.
+const (
+	// maxVizDepth bounds how deeply nested values are recorded.
+	maxVizDepth = 3
+	// maxVizElements bounds the elements, fields or entries recorded per value.
+	maxVizElements = 32
+	// maxVizString bounds the length of recorded strings.
+	maxVizString = 200
+)
+
+// vizRecorderTemplate records local variables after each statement. It is compiled into
+// the program alongside code.go and writes one JSON step per line as the program runs,
+// so the steps survive os.Exit and panics.
+//
+//go:embed viz_recorder.go.tmpl
+var vizRecorderTemplate string
+
+// vizRecorderSource is the recorder with its limits filled in.
+var vizRecorderSource = renderVizRecorder()
+
+// renderVizRecorder fills the recorder template's limits.
+func renderVizRecorder() string {
+	tmpl := template.Must(template.New(vizRecorderFile).Parse(vizRecorderTemplate))
+
+	var source strings.Builder
+	if err := tmpl.Execute(&source, map[string]any{
+		"Path":              vizContainerPath,
+		"StoppedGoroutines": VizStoppedGoroutines,
+		"StoppedStepLimit":  VizStoppedStepLimit,
+		"MaxSteps":          maxVizSteps,
+		"MaxDepth":          maxVizDepth,
+		"MaxElements":       maxVizElements,
+		"MaxString":         maxVizString,
+	}); err != nil {
+		panic(err)
+	}
+	return source.String()
+}
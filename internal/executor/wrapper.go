@@ -151,3 +151,60 @@ func PrepareForExecution(code string, isSnippet bool) string {
 
 	return code
 }
+
+// sourceMap maps positions in the prepared program back to the submitted code.
+type sourceMap struct {
+	lineOffset int // lines added before the user's code
+	colOffset  int // columns added by indenting the user's code
+	userLines  int // number of lines in the submitted code
+}
+
+// newSourceMap compares submitted and prepared code to find where the user's lines ended up.
+func newSourceMap(code, prepared string) sourceMap {
+	userLines := strings.Count(code, "\n") + 1
+	if code == prepared {
+		return sourceMap{userLines: userLines}
+	}
+
+	originalLines := strings.Split(code, "\n")
+	firstUser := -1
+	for i, line := range originalLines {
+		if strings.TrimSpace(line) != "" {
+			firstUser = i
+			break
+		}
+	}
+	if firstUser < 0 {
+		return sourceMap{userLines: userLines}
+	}
+
+	// Search after the generated package clause for the first user line
+	preparedLines := strings.Split(prepared, "\n")
+	target := strings.TrimSpace(originalLines[firstUser])
+	for i := 1; i < len(preparedLines); i++ {
+		if strings.TrimSpace(preparedLines[i]) != target {
+			continue
+		}
+		sm := sourceMap{lineOffset: i - firstUser, userLines: userLines}
+		// Snippets without a main function are indented one tab inside the generated main
+		if !HasMainFunc(strings.TrimSpace(code)) {
+			sm.colOffset = 1
+		}
+		return sm
+	}
+
+	return sourceMap{userLines: userLines}
+}
+
+// toUser converts a 1-based line and column in the prepared program to the submitted code.
+// It reports false for positions in generated code.
+func (sm sourceMap) toUser(line, col int) (userLine, userCol int, ok bool) {
+	userLine = line - sm.lineOffset
+	if userLine < 1 || userLine > sm.userLines {
+		return 0, 0, false
+	}
+	if col > 0 {
+		userCol = max(col-sm.colOffset, 1)
+	}
+	return userLine, userCol, true
+}
@@ -0,0 +1,264 @@
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jonesrussell/go-fundamentals-best-practices/internal/executor"
+)
+
+// idBytes is the number of random bytes in a job ID.
+const idBytes = 8
+
+var (
+	// ErrStoreFull is returned when the store holds the maximum number of jobs
+	// and none of them can be evicted.
+	ErrStoreFull = errors.New("job store is full")
+
+	// ErrStoreClosed is returned when a job is submitted after the store was closed.
+	ErrStoreClosed = errors.New("job store is closed")
+)
+
+// Status is the lifecycle state of a job.
+type Status string
+
+// Job statuses.
+const (
+	StatusQueued    Status = "queued"
+	StatusRunning   Status = "running"
+	StatusCompleted Status = "completed"
+	StatusFailed    Status = "failed"
+	StatusCanceled  Status = "canceled"
+)
+
+// RunFunc performs the work of a job.
+type RunFunc func(ctx context.Context) (*executor.ExecutionResult, error)
+
+// Job is a snapshot of an asynchronous execution.
+type Job struct {
+	ID         string                    `json:"id"`
+	Status     Status                    `json:"status"`
+	CreatedAt  time.Time                 `json:"createdAt"`
+	FinishedAt *time.Time                `json:"finishedAt,omitempty"`
+	Result     *executor.ExecutionResult `json:"result,omitempty"`
+	Error      string                    `json:"error,omitempty"`
+}
+
+// entry is a job together with the function that cancels it.
+type entry struct {
+	job    Job
+	cancel context.CancelFunc
+}
+
+// Store is a bounded in-memory job store. Finished jobs expire after a TTL.
+type Store struct {
+	mu      sync.Mutex
+	jobs    map[string]*entry
+	maxJobs int
+	ttl     time.Duration
+	timeout time.Duration
+	slots   chan struct{} // One token per running job
+	workers sync.WaitGroup
+	closed  bool
+}
+
+// NewStore creates a store holding at most maxJobs jobs, of which at most maxRunning
+// run at once while the rest wait their turn. Each job runs for at most timeout, not
+// counting the time it waited, and is kept for ttl after it finishes.
+func NewStore(maxJobs, maxRunning int, ttl, timeout time.Duration) *Store {
+	return &Store{
+		jobs:    make(map[string]*entry),
+		maxJobs: maxJobs,
+		ttl:     ttl,
+		timeout: timeout,
+		slots:   make(chan struct{}, maxRunning),
+	}
+}
+
+// Submit queues fn to run in the background and returns the new job.
+func (s *Store) Submit(fn RunFunc) (Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return Job{}, ErrStoreClosed
+	}
+
+	s.pruneLocked(time.Now())
+	if len(s.jobs) >= s.maxJobs && !s.evictOldestLocked() {
+		return Job{}, ErrStoreFull
+	}
+
+	id, err := newID()
+	if err != nil {
+		return Job{}, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	e := &entry{
+		job: Job{
+			ID:        id,
+			Status:    StatusQueued,
+			CreatedAt: time.Now(),
+		},
+		cancel: cancel,
+	}
+	s.jobs[id] = e
+
+	s.workers.Go(func() { s.run(ctx, e, fn) })
+
+	return e.job, nil
+}
+
+// run waits for a free slot, executes fn and records its outcome.
+func (s *Store) run(ctx context.Context, e *entry, fn RunFunc) {
+	defer e.cancel()
+
+	select {
+	case s.slots <- struct{}{}:
+		defer func() { <-s.slots }()
+	case <-ctx.Done():
+		s.finish(e, nil, ctx.Err())
+		return
+	}
+
+	s.mu.Lock()
+	canceled := e.job.Status == StatusCanceled
+	if !canceled {
+		e.job.Status = StatusRunning
+	}
+	s.mu.Unlock()
+	if canceled {
+		return
+	}
+
+	runCtx, cancelRun := context.WithTimeout(ctx, s.timeout)
+	defer cancelRun()
+
+	result, err := fn(runCtx)
+	s.finish(e, result, err)
+}
+
+// finish records the outcome of a job.
+func (s *Store) finish(e *entry, result *executor.ExecutionResult, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// A cancelled job keeps its status; the result of a killed run is meaningless
+	if e.job.Status == StatusCanceled {
+		return
+	}
+
+	now := time.Now()
+	e.job.FinishedAt = &now
+	if err != nil {
+		e.job.Status = StatusFailed
+		e.job.Error = err.Error()
+		return
+	}
+	e.job.Status = StatusCompleted
+	e.job.Result = result
+}
+
+// Get returns a job by ID.
+func (s *Store) Get(id string) (Job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pruneLocked(time.Now())
+	e, ok := s.jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+	return e.job, true
+}
+
+// Cancel stops a queued or running job. Cancelling a finished job is a no-op.
+func (s *Store) Cancel(id string) (Job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+
+	if e.job.Status == StatusQueued || e.job.Status == StatusRunning {
+		now := time.Now()
+		e.job.Status = StatusCanceled
+		e.job.FinishedAt = &now
+		e.cancel()
+	}
+
+	return e.job, true
+}
+
+// Close rejects new jobs, cancels the queued and running ones and waits for them to
+// finish, or for ctx to be done.
+func (s *Store) Close(ctx context.Context) error {
+	s.mu.Lock()
+	s.closed = true
+	for _, e := range s.jobs {
+		e.cancel()
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("wait for jobs: %w", ctx.Err())
+	}
+}
+
+// pruneLocked removes finished jobs older than the TTL.
+// Must be called with the lock held.
+func (s *Store) pruneLocked(now time.Time) {
+	for id, e := range s.jobs {
+		if e.job.FinishedAt != nil && now.Sub(*e.job.FinishedAt) > s.ttl {
+			delete(s.jobs, id)
+		}
+	}
+}
+
+// evictOldestLocked removes the finished job that finished first.
+// Queued and running jobs are never evicted. Must be called with the lock held.
+func (s *Store) evictOldestLocked() bool {
+	var oldestID string
+	var oldest time.Time
+	for id, e := range s.jobs {
+		if e.job.FinishedAt == nil {
+			continue
+		}
+		if oldestID == "" || e.job.FinishedAt.Before(oldest) {
+			oldestID = id
+			oldest = *e.job.FinishedAt
+		}
+	}
+
+	if oldestID == "" {
+		return false
+	}
+	delete(s.jobs, oldestID)
+	return true
+}
+
+// newID returns a random hex job ID.
+func newID() (string, error) {
+	b := make([]byte, idBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,110 @@
+package jobs_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/jonesrussell/go-fundamentals-best-practices/internal/executor"
+	"github.com/jonesrussell/go-fundamentals-best-practices/internal/jobs"
+)
+
+// blockingRun returns a job that runs until release is closed or it is cancelled.
+func blockingRun(release <-chan struct{}) jobs.RunFunc {
+	return func(ctx context.Context) (*executor.ExecutionResult, error) {
+		select {
+		case <-release:
+			return &executor.ExecutionResult{}, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// countStatuses returns the number of jobs in each status.
+func countStatuses(t *testing.T, store *jobs.Store, ids []string) map[jobs.Status]int {
+	t.Helper()
+	counts := make(map[jobs.Status]int)
+	for _, id := range ids {
+		job, ok := store.Get(id)
+		if !ok {
+			t.Fatalf("job %s not found", id)
+		}
+		counts[job.Status]++
+	}
+	return counts
+}
+
+func TestStoreConcurrencyCap(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		store := jobs.NewStore(10, 2, time.Minute, time.Minute)
+		defer func() { _ = store.Close(t.Context()) }()
+
+		var ids []string
+		releases := make(map[string]chan struct{})
+		for range 3 {
+			release := make(chan struct{})
+			job, err := store.Submit(blockingRun(release))
+			if err != nil {
+				t.Fatal(err)
+			}
+			ids = append(ids, job.ID)
+			releases[job.ID] = release
+		}
+
+		synctest.Wait()
+		counts := countStatuses(t, store, ids)
+		if counts[jobs.StatusRunning] != 2 || counts[jobs.StatusQueued] != 1 {
+			t.Fatalf("statuses = %v, want two running and one queued", counts)
+		}
+
+		// Finishing a running job hands its slot to the queued one
+		for _, id := range ids {
+			if job, _ := store.Get(id); job.Status == jobs.StatusRunning {
+				close(releases[id])
+				break
+			}
+		}
+		synctest.Wait()
+		counts = countStatuses(t, store, ids)
+		if counts[jobs.StatusCompleted] != 1 || counts[jobs.StatusRunning] != 2 {
+			t.Fatalf("statuses = %v, want the queued job running after the first completed", counts)
+		}
+
+		if err := store.Close(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+		counts = countStatuses(t, store, ids)
+		if counts[jobs.StatusCompleted] != 1 || counts[jobs.StatusFailed] != 2 {
+			t.Errorf("statuses = %v, want the running jobs stopped by Close", counts)
+		}
+		if _, err := store.Submit(blockingRun(nil)); !errors.Is(err, jobs.ErrStoreClosed) {
+			t.Errorf("Submit after Close = %v, want %v", err, jobs.ErrStoreClosed)
+		}
+	})
+}
+
+func TestStoreCloseTimeout(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		store := jobs.NewStore(10, 1, time.Minute, time.Hour)
+
+		// A job that ignores cancellation keeps Close waiting until its context is done
+		release := make(chan struct{})
+		if _, err := store.Submit(func(context.Context) (*executor.ExecutionResult, error) {
+			<-release
+			return nil, nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		synctest.Wait()
+
+		ctx, cancel := context.WithTimeout(t.Context(), time.Second)
+		defer cancel()
+		if err := store.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Close = %v, want %v", err, context.DeadlineExceeded)
+		}
+		close(release)
+	})
+}
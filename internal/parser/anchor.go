@@ -0,0 +1,38 @@
+package parser
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// headingLinkText matches a link or image in heading text, capturing the text shown
+var headingLinkText = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+
+// HeadingAnchors assigns anchors to the headings of one document the way GitHub does:
+// lower case, punctuation dropped, spaces turned into hyphens and a counter added to
+// anchors that repeat. The zero value is not usable; make one with make(HeadingAnchors).
+type HeadingAnchors map[string]int
+
+// Next returns the anchor for the next heading in the document
+func (a HeadingAnchors) Next(text string) string {
+	text = headingLinkText.ReplaceAllString(text, "$1")
+	anchor := strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			return unicode.ToLower(r)
+		case r == ' ':
+			return '-'
+		default:
+			return -1
+		}
+	}, text)
+
+	n := a[anchor]
+	a[anchor]++
+	if n > 0 {
+		return anchor + "-" + strconv.Itoa(n)
+	}
+	return anchor
+}
@@ -0,0 +1,138 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jonesrussell/go-fundamentals-best-practices/pkg/models"
+)
+
+// cheatsheetExt is the extension of cheatsheet files
+const cheatsheetExt = ".md"
+
+// Heading levels that matter in a cheatsheet: the title, entries and entries nested under a group
+const (
+	cheatsheetTitleLevel = 1
+	cheatsheetEntryLevel = 2
+	cheatsheetGroupLevel = 3
+)
+
+// cheatsheetHeading matches a Markdown heading, capturing its hashes and its text without the closing hashes
+var cheatsheetHeading = regexp.MustCompile(`^(#{1,6})\s+(.*?)(?:\s+#+)?\s*$`)
+
+// LoadCheatsheets parses every Markdown file in dir, in file name order
+func LoadCheatsheets(dir string) ([]*models.Cheatsheet, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cheatsheets directory: %w", err)
+	}
+
+	var cheatsheets []*models.Cheatsheet
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), cheatsheetExt) {
+			continue
+		}
+		cheatsheet, err := ParseCheatsheetFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		cheatsheets = append(cheatsheets, cheatsheet)
+	}
+	return cheatsheets, nil
+}
+
+// ParseCheatsheetFile parses a cheatsheet file, using its name without the extension as the ID
+func ParseCheatsheetFile(path string) (*models.Cheatsheet, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cheatsheet file: %w", err)
+	}
+	return ParseCheatsheet(strings.TrimSuffix(filepath.Base(path), cheatsheetExt), string(content)), nil
+}
+
+// ParseCheatsheet splits a cheatsheet into entries at its level 2 and 3 headings. A level 3
+// entry is grouped under the level 2 heading before it, and a level 2 heading with nothing
+// under it but such entries is not an entry itself. The title comes from the level 1 heading.
+// Code blocks become code examples, runnable when their fence says so as in section files.
+func ParseCheatsheet(id, content string) *models.Cheatsheet {
+	b := cheatsheetBuilder{
+		cheatsheet: &models.Cheatsheet{ID: id, Title: id, Entries: []models.CheatsheetEntry{}},
+		anchors:    make(HeadingAnchors),
+	}
+	fence := ""
+
+	for i, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if fence == "" && (strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")) {
+			fence = trimmed[:3]
+		} else if fence != "" && strings.HasPrefix(trimmed, fence) {
+			fence = ""
+		}
+
+		if match := cheatsheetHeading.FindStringSubmatch(trimmed); fence == "" && match != nil {
+			b.heading(len(match[1]), match[2], line, i+1)
+			continue
+		}
+		b.body = append(b.body, line)
+	}
+	b.flush()
+
+	return b.cheatsheet
+}
+
+// cheatsheetBuilder collects the entries of a cheatsheet as ParseCheatsheet reads its lines
+type cheatsheetBuilder struct {
+	cheatsheet *models.Cheatsheet
+	anchors    HeadingAnchors
+	titled     bool
+	group      string                  // Title of the last level 2 heading
+	current    *models.CheatsheetEntry // Entry being read, nil before the first one
+	startLine  int                     // Line of the current entry's heading
+	body       []string                // Lines under the current heading
+}
+
+// heading handles a heading line, starting a new entry at level 2 and 3
+func (b *cheatsheetBuilder) heading(level int, text, line string, lineNumber int) {
+	anchor := b.anchors.Next(text)
+
+	switch {
+	case level == cheatsheetTitleLevel && !b.titled && b.current == nil:
+		b.cheatsheet.Title = text
+		b.titled = true
+	case level == cheatsheetEntryLevel:
+		b.flush()
+		b.group = text
+		b.current = &models.CheatsheetEntry{ID: anchor, Title: text}
+		b.startLine = lineNumber
+	case level == cheatsheetGroupLevel:
+		b.flush()
+		b.current = &models.CheatsheetEntry{ID: anchor, Title: text, Group: b.group}
+		b.startLine = lineNumber
+	default:
+		b.body = append(b.body, line)
+	}
+}
+
+// flush finishes the current entry, or the intro before the first one. Entries with no
+// content are dropped.
+func (b *cheatsheetBuilder) flush() {
+	text := strings.Join(b.body, "\n")
+	b.body = nil
+
+	if b.current == nil {
+		b.cheatsheet.Intro = strings.TrimSpace(text)
+		return
+	}
+	entry := *b.current
+	entry.Content = strings.TrimSpace(text)
+	if entry.Content == "" {
+		return
+	}
+
+	// The content starts on the line after the heading
+	entry.CodeExamples = append([]models.CodeExample{}, extractCodeExamples(text, b.startLine, entry.ID)...)
+	b.cheatsheet.Entries = append(b.cheatsheet.Entries, entry)
+}
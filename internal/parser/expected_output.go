@@ -0,0 +1,93 @@
+package parser
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jonesrussell/go-fundamentals-best-practices/pkg/models"
+)
+
+// outputFenceLanguage marks a fenced block holding the expected output of the code block before it
+const outputFenceLanguage = "output"
+
+// Markers that start a trailing output comment, as in Go example tests
+const (
+	outputCommentPrefix          = "Output:"
+	unorderedOutputCommentPrefix = "Unordered output:"
+)
+
+// appendCodeExample adds a code example to examples, suffixing its ID if an earlier example
+// has the same one. An output fence is not an example of its own: it sets the expected output
+// of the example before it, and is dropped if there is none.
+func appendCodeExample(examples []models.CodeExample, example models.CodeExample) []models.CodeExample {
+	if example.Language != outputFenceLanguage {
+		example.ID = uniqueExampleID(examples, example.ID)
+		return append(examples, example)
+	}
+
+	if len(examples) > 0 {
+		last := &examples[len(examples)-1]
+		last.ExpectedOutput = example.ExpectedOutput
+		last.UnorderedOutput = example.UnorderedOutput
+	}
+	return examples
+}
+
+// uniqueExampleID returns id, or id with the first free "-2", "-3", ... suffix if an example
+// already uses it.
+func uniqueExampleID(examples []models.CodeExample, id string) string {
+	taken := func(candidate string) bool {
+		return slices.ContainsFunc(examples, func(e models.CodeExample) bool { return e.ID == candidate })
+	}
+
+	unique := id
+	for n := 2; taken(unique); n++ {
+		unique = fmt.Sprintf("%s-%d", id, n)
+	}
+	return unique
+}
+
+// trailingOutputComment reads the expected output from a trailing "// Output:" or
+// "// Unordered output:" comment block, which may be followed only by closing braces.
+// It reports false when the code has no such comment.
+func trailingOutputComment(code string) (output string, unordered, ok bool) {
+	lines := strings.Split(code, "\n")
+
+	// Skip the closing braces of the function the comment ends
+	end := len(lines)
+	for end > 0 {
+		trimmed := strings.TrimSpace(lines[end-1])
+		if trimmed != "" && strings.Trim(trimmed, "}") != "" {
+			break
+		}
+		end--
+	}
+
+	// Walk back over the trailing comment block to its output marker
+	for start := end - 1; start >= 0; start-- {
+		comment, isComment := strings.CutPrefix(strings.TrimSpace(lines[start]), "//")
+		if !isComment {
+			return "", false, false
+		}
+		comment = strings.TrimSpace(comment)
+
+		first, found := strings.CutPrefix(comment, outputCommentPrefix)
+		if !found {
+			first, found = strings.CutPrefix(comment, unorderedOutputCommentPrefix)
+			unordered = found
+		}
+		if !found {
+			continue
+		}
+
+		outputLines := []string{strings.TrimSpace(first)}
+		for _, line := range lines[start+1 : end] {
+			text := strings.TrimPrefix(strings.TrimSpace(line), "//")
+			outputLines = append(outputLines, strings.TrimPrefix(text, " "))
+		}
+		return strings.TrimSpace(strings.Join(outputLines, "\n")), unordered, true
+	}
+
+	return "", false, false
+}
@@ -0,0 +1,270 @@
+package parser
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/jonesrussell/go-fundamentals-best-practices/pkg/models"
+	"github.com/yuin/goldmark/ast"
+)
+
+// Fence attribute keys.
+const (
+	// fenceAttrToolchain pins the Go toolchain an example needs, e.g. toolchain=1.22.
+	fenceAttrToolchain = "toolchain"
+	// fenceAttrFixtures lists input files from the fixtures directory, e.g. fixtures=data.txt,words.txt.
+	fenceAttrFixtures = "fixtures"
+	// fenceAttrTimeout, fenceAttrMemory, fenceAttrCPU and fenceAttrMaxOutput set the resources
+	// an example runs with, e.g. timeout=5s memory=256 cpu=100 maxOutput=50000.
+	fenceAttrTimeout   = "timeout"
+	fenceAttrMemory    = "memory"
+	fenceAttrCPU       = "cpu"
+	fenceAttrMaxOutput = "maxOutput"
+	// fenceAttrCompileError, fenceAttrPanics and fenceAttrDeadlock mark an example that demonstrates
+	// an error. They may name text the error must contain, e.g. compile-error="declared and not used".
+	fenceAttrCompileError = "compile-error"
+	fenceAttrPanics       = "panics"
+	fenceAttrDeadlock     = "deadlock"
+	// fenceAttrID names an example explicitly, e.g. id=hello-world. Otherwise the ID is the
+	// section's ID and the example's number in the section, e.g. variables-2.
+	fenceAttrID = "id"
+)
+
+// expectedFailureKinds maps the fence attributes for expected failures to their kinds.
+var expectedFailureKinds = []struct{ attr, kind string }{
+	{fenceAttrCompileError, "compile-error"},
+	{fenceAttrPanics, "panic"},
+	{fenceAttrDeadlock, "deadlock"},
+}
+
+// fenceInfo is the parsed info string of a fenced code block, e.g. "go runnable toolchain=1.22".
+type fenceInfo struct {
+	language  string
+	runnable  bool
+	snippet   bool
+	unordered bool // Output fences only: lines may appear in any order
+	attrs     map[string]string
+	unknown   []string // Flags and attribute keys the parser does not recognise
+}
+
+// knownFenceAttrs are the key=value attribute keys the parser understands.
+var knownFenceAttrs = []string{
+	fenceAttrToolchain, fenceAttrFixtures,
+	fenceAttrTimeout, fenceAttrMemory, fenceAttrCPU, fenceAttrMaxOutput,
+	fenceAttrCompileError, fenceAttrPanics, fenceAttrDeadlock,
+	fenceAttrID,
+}
+
+// parseFenceInfo parses a code fence info string: the language, an optional
+// runnable/snippet flag and any key=value attributes. Values may be quoted to contain spaces.
+func parseFenceInfo(info string) fenceInfo {
+	parts := splitFenceInfo(info)
+	fi := fenceInfo{attrs: map[string]string{}}
+	if len(parts) == 0 {
+		return fi
+	}
+
+	fi.language = parts[0]
+	for _, part := range parts[1:] {
+		if key, value, found := strings.Cut(part, "="); found {
+			if !slices.Contains(knownFenceAttrs, key) {
+				fi.unknown = append(fi.unknown, key)
+			}
+			fi.attrs[key] = strings.Trim(value, `"`)
+			continue
+		}
+
+		switch part {
+		case "runnable":
+			fi.runnable = true
+		case "snippet":
+			fi.runnable = true
+			fi.snippet = true
+		case "unordered":
+			fi.unordered = true
+		case fenceAttrCompileError, fenceAttrPanics, fenceAttrDeadlock:
+			fi.attrs[part] = ""
+		default:
+			fi.unknown = append(fi.unknown, part)
+		}
+	}
+
+	return fi
+}
+
+// splitFenceInfo splits an info string on whitespace outside double quotes.
+func splitFenceInfo(info string) []string {
+	var parts []string
+	var part strings.Builder
+	quoted := false
+
+	for _, r := range info {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			part.WriteRune(r)
+		case unicode.IsSpace(r) && !quoted:
+			if part.Len() > 0 {
+				parts = append(parts, part.String())
+				part.Reset()
+			}
+		default:
+			part.WriteRune(r)
+		}
+	}
+	if part.Len() > 0 {
+		parts = append(parts, part.String())
+	}
+
+	return parts
+}
+
+// newCodeExample builds a code example from a fence info string and its code. Its ID is the
+// fence's id attribute, or defaultID. For an output fence, the code is the expected output of
+// the example before it.
+func newCodeExample(info, code, defaultID string) models.CodeExample {
+	fi := parseFenceInfo(info)
+	id := cmp.Or(fi.attrs[fenceAttrID], defaultID)
+
+	if fi.language == outputFenceLanguage {
+		return models.CodeExample{
+			ID:              id,
+			Language:        fi.language,
+			ExpectedOutput:  code,
+			UnorderedOutput: fi.unordered,
+		}
+	}
+
+	failure := expectedFailure(id, fi.attrs)
+
+	// Examples that demonstrate an error are run to show it
+	runnable := fi.runnable || failure != nil
+	// Auto-detect: if Go code has "package main", it's runnable
+	if !runnable && fi.language == "go" && strings.Contains(code, "package main") {
+		runnable = true
+	}
+
+	example := models.CodeExample{
+		ID:              id,
+		Code:            code,
+		Language:        fi.language,
+		Runnable:        runnable,
+		Snippet:         fi.snippet,
+		Toolchain:       fi.attrs[fenceAttrToolchain],
+		Fixtures:        fixtureRefs(fi.attrs[fenceAttrFixtures]),
+		Run:             runSettings(id, fi.attrs),
+		ExpectedFailure: failure,
+	}
+	if fi.language == "go" {
+		example.ExpectedOutput, example.UnorderedOutput, _ = trailingOutputComment(code)
+	}
+	return example
+}
+
+// runSettings reads the resource attributes of a fence, or returns nil when it has none.
+// Invalid values are reported and ignored.
+func runSettings(id string, attrs map[string]string) *models.RunSettings {
+	var run models.RunSettings
+
+	if value, ok := attrs[fenceAttrTimeout]; ok {
+		if d, err := time.ParseDuration(value); err == nil && d > 0 {
+			run.Timeout = value
+		} else {
+			fmt.Fprintf(os.Stderr, "Warning: %s: invalid %s %q\n", id, fenceAttrTimeout, value)
+		}
+	}
+
+	ints := map[string]*int{
+		fenceAttrMemory:    &run.Memory,
+		fenceAttrCPU:       &run.CPU,
+		fenceAttrMaxOutput: &run.MaxOutput,
+	}
+	for key, target := range ints {
+		value, ok := attrs[key]
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			fmt.Fprintf(os.Stderr, "Warning: %s: invalid %s %q\n", id, key, value)
+			continue
+		}
+		*target = n
+	}
+
+	if run == (models.RunSettings{}) {
+		return nil
+	}
+	return &run
+}
+
+// expectedFailure reads the expected failure attribute of a fence, or returns nil when it has none.
+// Only one failure can be expected; extra ones are reported and ignored.
+func expectedFailure(id string, attrs map[string]string) *models.ExpectedFailure {
+	var failure *models.ExpectedFailure
+	for _, k := range expectedFailureKinds {
+		message, ok := attrs[k.attr]
+		if !ok {
+			continue
+		}
+		if failure != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %s: %s ignored, already expecting %s\n", id, k.attr, failure.Kind)
+			continue
+		}
+		failure = &models.ExpectedFailure{Kind: k.kind, Message: message}
+	}
+	return failure
+}
+
+// fixtureRefs returns fixtures named by a comma-separated fence attribute, without content.
+// Content is filled in by loadFixtures.
+func fixtureRefs(names string) []models.Fixture {
+	var fixtures []models.Fixture
+	for name := range strings.SplitSeq(names, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			fixtures = append(fixtures, models.Fixture{Name: name})
+		}
+	}
+	return fixtures
+}
+
+// Fence is a fenced code block found in section markdown, for tools that check content.
+type Fence struct {
+	Line     int      // Line of the opening fence, 1-based
+	Language string   // First word of the info string
+	ID       string   // Explicit id attribute, if any
+	Unknown  []string // Flags and attribute keys the parser does not recognise
+}
+
+// Fences returns the fenced code blocks in section markdown, found the same way
+// ParseSectionFile finds code examples.
+func Fences(content string) []Fence {
+	doc, source := parseMarkdown(content)
+	lines := newLineIndex(source, 0)
+
+	var fences []Fence
+	// Walk only fails when the walker does
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		block, ok := n.(*ast.FencedCodeBlock)
+		if !entering || !ok {
+			return ast.WalkContinue, nil
+		}
+		if fenced, ok := readFencedCode(block, source, lines); ok {
+			fi := parseFenceInfo(fenced.info)
+			fences = append(fences, Fence{
+				Line:     fenced.line,
+				Language: fi.language,
+				ID:       fi.attrs[fenceAttrID],
+				Unknown:  fi.unknown,
+			})
+		}
+		return ast.WalkSkipChildren, nil
+	})
+	return fences
+}
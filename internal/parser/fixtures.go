@@ -0,0 +1,66 @@
+package parser
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"unicode/utf8"
+
+	"github.com/jonesrussell/go-fundamentals-best-practices/pkg/models"
+)
+
+// fixturesDirName is the directory next to the markdown files that holds example fixtures
+const fixturesDirName = "fixtures"
+
+// fixtureEncodingBase64 marks fixture content that is not UTF-8 text
+const fixtureEncodingBase64 = "base64"
+
+// loadFixtures reads the content of the fixtures each code example references from dir.
+// Missing fixtures are reported and dropped so the rest of the section still loads.
+func loadFixtures(dir string, examples []models.CodeExample) {
+	for i := range examples {
+		if len(examples[i].Fixtures) == 0 {
+			continue
+		}
+
+		var loaded []models.Fixture
+		for _, ref := range examples[i].Fixtures {
+			fixture, err := readFixture(dir, ref.Name)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: fixture for %s: %v\n", examples[i].ID, err)
+				continue
+			}
+			loaded = append(loaded, fixture)
+		}
+		examples[i].Fixtures = loaded
+	}
+}
+
+// readFixture reads a fixture, keeping text as is and base64-encoding binary files
+func readFixture(dir, name string) (models.Fixture, error) {
+	if !filepath.IsLocal(name) {
+		return models.Fixture{}, fmt.Errorf("fixture name %q must be a relative path inside %s", name, fixturesDirName)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return models.Fixture{}, fmt.Errorf("failed to read fixture: %w", err)
+	}
+
+	if utf8.Valid(data) {
+		return models.Fixture{Name: name, Content: string(data)}, nil
+	}
+	return models.Fixture{
+		Name:     name,
+		Content:  base64.StdEncoding.EncodeToString(data),
+		Encoding: fixtureEncodingBase64,
+	}, nil
+}
+
+// loadSectionFixtures reads fixtures for every code example in the sections
+func loadSectionFixtures(dir string, sections []models.Section) {
+	for i := range sections {
+		loadFixtures(dir, sections[i].CodeExamples)
+	}
+}
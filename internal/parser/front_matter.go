@@ -0,0 +1,100 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// frontMatterDelimiter opens and closes the YAML front matter block of a section file
+const frontMatterDelimiter = "---"
+
+// frontMatterDelimiterLines is the number of delimiter lines around the front matter
+const frontMatterDelimiterLines = 2
+
+// sectionFrontMatter is the optional YAML front matter at the top of a section file.
+// Any field it sets takes precedence over what the heuristics scrape from the markdown.
+type sectionFrontMatter struct {
+	ID            string   `yaml:"id"`
+	Title         string   `yaml:"title"`
+	Duration      string   `yaml:"duration"`
+	Objectives    []string `yaml:"objectives"`
+	Tags          []string `yaml:"tags"`
+	Prerequisites []string `yaml:"prerequisites"` // IDs of sections to read first
+	MinGoVersion  string   `yaml:"minGoVersion"`  // e.g. "1.22"
+}
+
+// splitFrontMatter separates a leading YAML front matter block from the markdown after it.
+// It reports false when the content has no front matter.
+func splitFrontMatter(content string) (frontMatter, body string, ok bool) {
+	normalized := strings.ReplaceAll(content, "\r\n", "\n")
+	rest, found := strings.CutPrefix(normalized, frontMatterDelimiter+"\n")
+	if !found {
+		return "", content, false
+	}
+
+	// The block ends at the first line holding only the delimiter
+	if frontMatter, body, found = strings.Cut(rest, "\n"+frontMatterDelimiter+"\n"); found {
+		return frontMatter, body, true
+	}
+	if frontMatter, found = strings.CutSuffix(rest, "\n"+frontMatterDelimiter); found {
+		return frontMatter, "", true
+	}
+	return "", content, false
+}
+
+// parseFrontMatter reads the front matter of a section file and returns the markdown after it,
+// along with the number of lines the front matter took up
+func parseFrontMatter(content string) (matter sectionFrontMatter, body string, lines int, err error) {
+	frontMatter, body, ok := splitFrontMatter(content)
+	if !ok {
+		return matter, content, 0, nil
+	}
+
+	lines = strings.Count(frontMatter, "\n") + 1 + frontMatterDelimiterLines
+	if unmarshalErr := yaml.Unmarshal([]byte(frontMatter), &matter); unmarshalErr != nil {
+		return sectionFrontMatter{}, body, lines, fmt.Errorf("invalid front matter: %w", unmarshalErr)
+	}
+	return matter, body, lines, nil
+}
+
+// SplitFrontMatter returns the markdown of a section file without its front matter, and the
+// number of lines the front matter took up
+func SplitFrontMatter(content string) (body string, lines int) {
+	_, body, lines, _ = parseFrontMatter(content)
+	return body, lines
+}
+
+// ValidateFrontMatter reports malformed front matter and keys a section file's front matter
+// does not support, which ParseSectionFile ignores
+func ValidateFrontMatter(content string) error {
+	frontMatter, _, ok := splitFrontMatter(content)
+	if !ok {
+		return nil
+	}
+
+	var matter sectionFrontMatter
+	dec := yaml.NewDecoder(strings.NewReader(frontMatter))
+	dec.KnownFields(true)
+	if err := dec.Decode(&matter); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("invalid front matter: %w", err)
+	}
+	return nil
+}
+
+// slugSeparators matches runs of characters that are not allowed in a slug
+var slugSeparators = regexp.MustCompile(`[^a-z0-9]+`)
+
+// orderPrefix matches the ordering prefix of a section filename, e.g. "03-"
+var orderPrefix = regexp.MustCompile(`^\d+[-_.]`)
+
+// sectionSlug derives a section ID from its filename, e.g. "03-variables.md" -> "variables"
+func sectionSlug(filename string) string {
+	name := strings.TrimSuffix(filename, ".md")
+	name = orderPrefix.ReplaceAllString(name, "")
+	return strings.Trim(slugSeparators.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
@@ -0,0 +1,121 @@
+package parser_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jonesrussell/go-fundamentals-best-practices/internal/parser"
+)
+
+// update rewrites the golden files from the parser's output: go test ./internal/parser -update
+var update = flag.Bool("update", false, "update golden files")
+
+// goldenDir holds the parser's expected output for the content in the repository and the fixtures
+const goldenDir = "testdata/golden"
+
+// fixturesTutorialID is the tutorial in testdata whose sections cover Markdown the tutorials do not
+const fixturesTutorialID = "fixtures"
+
+// TestTutorialsGolden parses every tutorial and compares its sections with the golden files.
+// Section content is left out, since it is the file as written and its rendering.
+func TestTutorialsGolden(t *testing.T) {
+	tutorials, err := parser.NewTutorialParser(filepath.Join("..", "..", "tutorials")).LoadAllTutorials()
+	if err != nil {
+		t.Fatalf("failed to load tutorials: %v", err)
+	}
+
+	for _, tutorial := range tutorials {
+		t.Run(tutorial.ID, func(t *testing.T) {
+			for i := range tutorial.Sections {
+				tutorial.Sections[i].Content = ""
+				tutorial.Sections[i].ContentHTML = ""
+			}
+			checkGolden(t, "tutorial-"+tutorial.ID+".json", tutorial)
+		})
+	}
+}
+
+// TestSectionFixturesGolden parses the fixture sections, which cover tilde fences, fences
+// nested in lists and in longer fences, and prose headings that start with "Key"
+func TestSectionFixturesGolden(t *testing.T) {
+	p := parser.NewDirectoryParser("testdata")
+	files, err := p.ListSectionFiles(fixturesTutorialID)
+	if err != nil {
+		t.Fatalf("failed to list fixture sections: %v", err)
+	}
+
+	for i, file := range files {
+		t.Run(file, func(t *testing.T) {
+			section, err := p.ParseSectionFile(fixturesTutorialID, file, i+1)
+			if err != nil {
+				t.Fatalf("failed to parse fixture section: %v", err)
+			}
+			section.Content = ""
+			section.ContentHTML = ""
+			checkGolden(t, "section-"+section.ID+".json", section)
+		})
+	}
+}
+
+// TestSingleFileTutorialGolden parses the fixture single-file tutorial, whose ### headings start
+// its sections
+func TestSingleFileTutorialGolden(t *testing.T) {
+	tutorial, err := parser.NewTutorialParser("testdata").ParseTutorial("Tutorial-99-Single-File.md")
+	if err != nil {
+		t.Fatalf("failed to parse fixture tutorial: %v", err)
+	}
+
+	for i := range tutorial.Sections {
+		tutorial.Sections[i].Content = ""
+		tutorial.Sections[i].ContentHTML = ""
+	}
+	checkGolden(t, "single-file-tutorial-"+tutorial.ID+".json", tutorial)
+}
+
+// TestCheatsheetsGolden parses every cheatsheet and compares it with the golden files
+func TestCheatsheetsGolden(t *testing.T) {
+	cheatsheets, err := parser.LoadCheatsheets(filepath.Join("..", "..", "cheatsheets"))
+	if err != nil {
+		t.Fatalf("failed to load cheatsheets: %v", err)
+	}
+
+	for _, cheatsheet := range cheatsheets {
+		t.Run(cheatsheet.ID, func(t *testing.T) {
+			checkGolden(t, "cheatsheet-"+cheatsheet.ID+".json", cheatsheet)
+		})
+	}
+}
+
+// checkGolden compares a value, as indented JSON, with a golden file, or writes the file with -update
+func checkGolden(t *testing.T, name string, value any) {
+	t.Helper()
+
+	got, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	got = append(got, '\n')
+
+	path := filepath.Join(goldenDir, name)
+	if *update {
+		if err := os.MkdirAll(goldenDir, 0o750); err != nil {
+			t.Fatalf("failed to create golden directory: %v", err)
+		}
+		if err := os.WriteFile(path, got, 0o600); err != nil {
+			t.Fatalf("failed to write golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file (run with -update to create it): %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s does not match the parser's output; run go test ./internal/parser -update and review the diff", path)
+	}
+}
@@ -0,0 +1,165 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	goldmarkparser "github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+)
+
+// Attributes rendered code blocks carry
+const (
+	// attrExampleID is the ID of the code example a code block is
+	attrExampleID = "data-example-id"
+	// attrOutputFor is the ID of the code example an output block is the expected output of
+	attrOutputFor = "data-output-for"
+	// attrLanguage is the language of a code block, e.g. "go"
+	attrLanguage = "data-language"
+)
+
+// highlightStyle is the chroma style code is highlighted with, the theme the frontend uses
+const highlightStyle = "github-dark"
+
+// codeBlockRendererPriority puts codeBlockRenderer ahead of goldmark's HTML renderer (priority 1000)
+const codeBlockRendererPriority = 100
+
+// markdown parses and renders Markdown as GitHub does, with footnotes and heading IDs
+var markdown = goldmark.New(
+	goldmark.WithExtensions(extension.GFM, extension.Footnote),
+	goldmark.WithParserOptions(goldmarkparser.WithAutoHeadingID()),
+	goldmark.WithRendererOptions(
+		renderer.WithNodeRenderers(util.Prioritized(codeBlockRenderer{}, codeBlockRendererPriority)),
+	),
+)
+
+// RenderHTML renders Markdown to HTML. Headings get the anchors HeadingAnchors gives them,
+// fenced code is highlighted, and each code block carries a data-example-id attribute with
+// the ID of the code example ParseSectionFile makes of it in a section with ID sectionID;
+// output blocks carry data-output-for instead.
+func RenderHTML(content, sectionID string) (string, error) {
+	doc, source := parseMarkdown(content)
+	readSectionMarkdown(doc, source, 0, sectionID)
+	return renderDocument(doc, source)
+}
+
+// renderDocument renders a document parsed by parseMarkdown, once readSectionMarkdown has
+// tagged its code blocks
+func renderDocument(doc ast.Node, source []byte) (string, error) {
+	var buf bytes.Buffer
+	if err := markdown.Renderer().Render(&buf, source, doc); err != nil {
+		return "", fmt.Errorf("failed to render markdown: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// headingIDs gives headings the anchors HeadingAnchors does, so links made from section
+// headings, such as search results, find them in the rendered HTML
+type headingIDs struct {
+	anchors HeadingAnchors
+}
+
+// Generate returns the anchor of a heading's text
+func (h headingIDs) Generate(value []byte, _ ast.NodeKind) []byte {
+	return []byte(h.anchors.Next(string(value)))
+}
+
+// Put records an ID set explicitly
+func (h headingIDs) Put(value []byte) {
+	h.anchors[string(value)]++
+}
+
+// codeBlockRenderer renders fenced code blocks highlighted, with their attributes
+type codeBlockRenderer struct{}
+
+// RegisterFuncs registers the renderer for fenced code blocks
+func (r codeBlockRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindFencedCodeBlock, r.renderFencedCodeBlock)
+}
+
+// renderFencedCodeBlock writes a fenced code block as a highlighted <pre> element
+func (r codeBlockRenderer) renderFencedCodeBlock(
+	w util.BufWriter, source []byte, node ast.Node, entering bool,
+) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	block, ok := node.(*ast.FencedCodeBlock)
+	if !ok {
+		return ast.WalkContinue, nil
+	}
+
+	language := string(block.Language(source))
+	var code strings.Builder
+	for i := range block.Lines().Len() {
+		line := block.Lines().At(i)
+		code.Write(line.Value(source))
+	}
+
+	attrs := codeBlockAttributes(block, language)
+	lexer := lexers.Get(language)
+	if lexer == nil {
+		_, err := fmt.Fprintf(w, "<pre%s><code>%s</code></pre>\n", attrs, html.EscapeString(code.String()))
+		return ast.WalkSkipChildren, err
+	}
+
+	tokens, err := chroma.Coalesce(lexer).Tokenise(nil, code.String())
+	if err != nil {
+		return ast.WalkStop, fmt.Errorf("failed to highlight %s code: %w", language, err)
+	}
+	formatter := chromahtml.New(chromahtml.WithPreWrapper(preWrapper{attrs: attrs}))
+	if err := formatter.Format(w, styles.Get(highlightStyle), tokens); err != nil {
+		return ast.WalkStop, fmt.Errorf("failed to highlight %s code: %w", language, err)
+	}
+	_, err = w.WriteString("\n")
+	return ast.WalkSkipChildren, err
+}
+
+// codeBlockAttributes returns the attributes of a code block's <pre> element, each with a
+// leading space
+func codeBlockAttributes(block *ast.FencedCodeBlock, language string) string {
+	var attrs strings.Builder
+	if language != "" {
+		fmt.Fprintf(&attrs, ` class="language-%s" %s="%s"`,
+			html.EscapeString(language), attrLanguage, html.EscapeString(language))
+	}
+	for _, name := range []string{attrExampleID, attrOutputFor} {
+		if value, ok := block.AttributeString(name); ok {
+			if b, isBytes := value.([]byte); isBytes {
+				fmt.Fprintf(&attrs, ` %s="%s"`, name, html.EscapeString(string(b)))
+			}
+		}
+	}
+	return attrs.String()
+}
+
+// preWrapper wraps highlighted code in a <pre> element with the code block's attributes
+type preWrapper struct {
+	attrs string
+}
+
+// Start opens the <pre> and <code> elements
+func (p preWrapper) Start(code bool, styleAttr string) string {
+	if code {
+		return "<pre" + styleAttr + p.attrs + "><code>"
+	}
+	return "<pre" + styleAttr + p.attrs + ">"
+}
+
+// End closes the elements Start opened
+func (p preWrapper) End(code bool) string {
+	if code {
+		return "</code></pre>"
+	}
+	return "</pre>"
+}
@@ -1,12 +1,13 @@
 package parser
 
 import (
+	"cmp"
 	"fmt"
 	"os"
 	"path/filepath"
-	"regexp"
 	"sort"
 	"strings"
+	"unicode"
 
 	"github.com/jonesrussell/go-fundamentals-best-practices/pkg/models"
 	"gopkg.in/yaml.v3"
@@ -23,6 +24,33 @@ type TutorialConfig struct {
 	TableOfContents string   `yaml:"tableOfContents"`
 }
 
+// tutorialRefPrefix marks a prerequisite that names another tutorial by ID, e.g. "tutorial-2".
+// Any other prerequisite is free text.
+const tutorialRefPrefix = "tutorial-"
+
+// PrerequisiteTutorialID returns the ID of the tutorial a prerequisite references, or false
+// when the prerequisite is free text
+func PrerequisiteTutorialID(prerequisite string) (string, bool) {
+	id, ok := strings.CutPrefix(strings.TrimSpace(prerequisite), tutorialRefPrefix)
+	if !ok || id == "" || strings.ContainsFunc(id, unicode.IsSpace) {
+		return "", false
+	}
+	return id, true
+}
+
+// splitPrerequisites separates free-text prerequisites from references to other tutorials
+func splitPrerequisites(prerequisites []string) (text, tutorialIDs []string) {
+	text = make([]string, 0, len(prerequisites))
+	for _, prerequisite := range prerequisites {
+		if id, ok := PrerequisiteTutorialID(prerequisite); ok {
+			tutorialIDs = append(tutorialIDs, id)
+		} else {
+			text = append(text, prerequisite)
+		}
+	}
+	return text, tutorialIDs
+}
+
 // DirectoryParser handles parsing of directory-based tutorials
 type DirectoryParser struct {
 	tutorialsDir string
@@ -127,28 +155,55 @@ func (p *DirectoryParser) ParseSectionFile(tutorialID, filename string, order in
 		return nil, fmt.Errorf("failed to read section file: %w", err)
 	}
 
-	contentStr := string(content)
+	// Front matter is optional; a malformed block is reported and the heuristics used instead
+	matter, contentStr, frontMatterLines, err := parseFrontMatter(string(content))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: section %s: %v\n", filename, err)
+	}
 
 	section := &models.Section{
-		ID:             fmt.Sprintf("section-%d", order),
+		ID:             cmp.Or(matter.ID, sectionSlug(filename), fmt.Sprintf("section-%d", order)),
 		Order:          order,
 		Topics:         []string{},
 		CodeExamples:   []models.CodeExample{},
 		TeachingPoints: []string{},
 		Content:        contentStr,
+		Source:         filepath.ToSlash(filepath.Join(fmt.Sprintf("tutorial-%s", tutorialID), "sections", filename)),
+		Objectives:     matter.Objectives,
+		Tags:           matter.Tags,
+		Prerequisites:  matter.Prerequisites,
+		MinGoVersion:   strings.TrimPrefix(matter.MinGoVersion, "go"),
 	}
 
-	// Extract title from first heading
-	section.Title = extractSectionTitle(contentStr)
+	// One walk of the Markdown finds everything else; positions count from the top of the file
+	doc, source := parseMarkdown(contentStr)
+	md := readSectionMarkdown(doc, source, frontMatterLines, section.ID)
+
+	// Front matter wins; otherwise take the title from the first heading and the
+	// duration from a **Duration:** line
+	section.Title = cmp.Or(matter.Title, md.title, untitledSection)
+	section.Duration = cmp.Or(matter.Duration, md.duration)
+	positions := md.positions
+	if matter.Title != "" {
+		positions.Title = nil
+	}
+	if matter.Duration != "" {
+		positions.Duration = nil
+	}
+	section.Positions = &positions
 
-	// Parse topics
-	section.Topics = extractTopics(contentStr)
+	section.Topics = md.topics
+	section.TeachingPoints = md.teachingPoints
 
-	// Parse code examples with attributes
-	section.CodeExamples = extractCodeExamples(contentStr)
+	// Code examples load their fixtures from sections/fixtures
+	section.CodeExamples = md.examples
+	loadFixtures(filepath.Join(filepath.Dir(filePath), fixturesDirName), section.CodeExamples)
 
-	// Parse teaching points
-	section.TeachingPoints = extractTeachingPoints(contentStr)
+	// The walk tagged the code blocks with their examples' IDs for rendering
+	section.ContentHTML, err = renderDocument(doc, source)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: section %s: %v\n", filename, err)
+	}
 
 	return section, nil
 }
@@ -161,13 +216,15 @@ func (p *DirectoryParser) ParseTutorialFromDirectory(tutorialID string, includeI
 		return nil, err
 	}
 
+	prerequisites, requiredIDs := splitPrerequisites(config.Prerequisites)
 	tutorial := &models.Tutorial{
 		ID:              config.ID,
 		Title:           config.Title,
 		Duration:        config.Duration,
 		Difficulty:      config.Difficulty,
 		Level:           config.Level,
-		Prerequisites:   config.Prerequisites,
+		Prerequisites:   prerequisites,
+		RequiredIDs:     requiredIDs,
 		TableOfContents: config.TableOfContents,
 		Sections:        []models.Section{},
 	}
@@ -183,6 +240,7 @@ func (p *DirectoryParser) ParseTutorialFromDirectory(tutorialID string, includeI
 		return nil, err
 	}
 
+	sectionIDs := make(map[string]bool)
 	for i, filename := range sectionFiles {
 		section, parseErr := p.ParseSectionFile(tutorialID, filename, i+1)
 		if parseErr != nil {
@@ -191,6 +249,13 @@ func (p *DirectoryParser) ParseTutorialFromDirectory(tutorialID string, includeI
 			continue
 		}
 
+		// Section IDs key stored progress and name code examples, so a clash fails the tutorial
+		// rather than giving a section an ID that depends on its position
+		if sectionIDs[section.ID] {
+			return nil, fmt.Errorf("section %s: duplicate section ID %q", filename, section.ID)
+		}
+		sectionIDs[section.ID] = true
+
 		// Load instructor notes if requested
 		if includeInstructorNotes {
 			notes, _ := p.LoadInstructorNotes(tutorialID, filename)
@@ -227,148 +292,15 @@ func (p *DirectoryParser) GetTutorialMetadataFromDirectory(tutorialID string) (*
 		return nil, err
 	}
 
+	prerequisites, requiredIDs := splitPrerequisites(config.Prerequisites)
 	return &models.TutorialMetadata{
 		ID:            config.ID,
 		Title:         config.Title,
 		Duration:      config.Duration,
 		Difficulty:    config.Difficulty,
 		Level:         config.Level,
-		Prerequisites: config.Prerequisites,
+		Prerequisites: prerequisites,
+		RequiredIDs:   requiredIDs,
 		SectionCount:  len(sectionFiles),
 	}, nil
 }
-
-// Helper functions
-
-func extractSectionTitle(content string) string {
-	lines := strings.Split(content, "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "# ") {
-			return strings.TrimSpace(strings.TrimPrefix(line, "#"))
-		}
-		if strings.HasPrefix(line, "## ") {
-			return strings.TrimSpace(strings.TrimPrefix(line, "##"))
-		}
-	}
-	return "Untitled Section"
-}
-
-func extractTopics(content string) []string {
-	var topics []string
-	lines := strings.Split(content, "\n")
-	inTopics := false
-
-	for _, line := range lines {
-		trimmed := strings.TrimSpace(line)
-
-		// Check for topics section header
-		if strings.Contains(strings.ToLower(trimmed), "topics to cover") ||
-			strings.Contains(strings.ToLower(trimmed), "## topics") {
-			inTopics = true
-			continue
-		}
-
-		// Stop at next section
-		if inTopics && (strings.HasPrefix(trimmed, "##") || strings.HasPrefix(trimmed, "```")) {
-			break
-		}
-
-		// Extract topic items
-		if inTopics && strings.HasPrefix(trimmed, "-") {
-			topic := strings.TrimSpace(strings.TrimPrefix(trimmed, "-"))
-			topic = strings.Trim(topic, "`")
-			if topic != "" {
-				topics = append(topics, topic)
-			}
-		}
-	}
-
-	return topics
-}
-
-// codeBlockMatchGroups is the expected number of capture groups in code block regex:
-// full match, language, attribute (optional), code content
-const codeBlockMatchGroups = 4
-
-// codeBlockRegex matches code blocks with optional attributes.
-// Matches: ```go, ```go runnable, ```go snippet
-// Groups: [0]=full match, [1]=language, [2]=attribute, [3]=code content
-var codeBlockRegex = regexp.MustCompile("(?s)```(\\w+)(?:\\s+(runnable|snippet))?\\s*\\n(.*?)```")
-
-func extractCodeExamples(content string) []models.CodeExample {
-	var examples []models.CodeExample
-
-	matches := codeBlockRegex.FindAllStringSubmatch(content, -1)
-
-	for i, match := range matches {
-		if len(match) < codeBlockMatchGroups {
-			continue
-		}
-
-		language := match[1]
-		attribute := match[2] // "runnable", "snippet", or ""
-		code := strings.TrimSpace(match[3])
-
-		// Determine if runnable
-		runnable := false
-		snippet := false
-
-		if attribute == "runnable" {
-			runnable = true
-		} else if attribute == "snippet" {
-			runnable = true
-			snippet = true
-		} else if language == "go" {
-			// Auto-detect: if has package main, it's runnable
-			if strings.Contains(code, "package main") {
-				runnable = true
-			}
-		}
-
-		example := models.CodeExample{
-			ID:       fmt.Sprintf("code-%d", i),
-			Code:     code,
-			Language: language,
-			Runnable: runnable,
-			Snippet:  snippet,
-		}
-		examples = append(examples, example)
-	}
-
-	return examples
-}
-
-func extractTeachingPoints(content string) []string {
-	var points []string
-	lines := strings.Split(content, "\n")
-	inPoints := false
-
-	for _, line := range lines {
-		trimmed := strings.TrimSpace(line)
-
-		// Check for teaching points section header
-		if strings.Contains(strings.ToLower(trimmed), "key teaching points") ||
-			strings.Contains(strings.ToLower(trimmed), "teaching points") ||
-			strings.Contains(strings.ToLower(trimmed), "## key") {
-			inPoints = true
-			continue
-		}
-
-		// Stop at next section
-		if inPoints && strings.HasPrefix(trimmed, "##") {
-			break
-		}
-
-		// Extract teaching point items
-		if inPoints && strings.HasPrefix(trimmed, "-") {
-			point := strings.TrimSpace(strings.TrimPrefix(trimmed, "-"))
-			point = strings.Trim(point, "`")
-			if point != "" {
-				points = append(points, point)
-			}
-		}
-	}
-
-	return points
-}
@@ -0,0 +1,321 @@
+package parser
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/jonesrussell/go-fundamentals-best-practices/pkg/models"
+	"github.com/yuin/goldmark/ast"
+	goldmarkparser "github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+// untitledSection is the title of a section with no title heading or front matter title
+const untitledSection = "Untitled Section"
+
+// durationPrefix starts the line that gives a section's reading time, e.g. "**Duration:** 3-4 minutes"
+const durationPrefix = "**Duration:**"
+
+// maxTitleLevel is the deepest heading level a section takes its title from
+const maxTitleLevel = 2
+
+// Headings that introduce a section's lists, in lower case without a trailing colon
+var (
+	topicsHeadings         = []string{"topics to cover", "topics"}
+	teachingPointsHeadings = []string{"key teaching points", "teaching points", "key takeaways"}
+)
+
+// sectionList is the kind of list the items under a heading are collected into
+type sectionList int
+
+const (
+	listNone sectionList = iota
+	listTopics
+	listTeachingPoints
+)
+
+// sectionMarkdown is what a section's Markdown yields
+type sectionMarkdown struct {
+	title          string
+	duration       string
+	topics         []string
+	teachingPoints []string
+	examples       []models.CodeExample
+	positions      models.SectionPositions
+}
+
+// parseMarkdown parses Markdown as it is rendered: GitHub Flavored Markdown with footnotes,
+// and headings with the anchors HeadingAnchors gives them
+func parseMarkdown(content string) (ast.Node, []byte) {
+	source := []byte(content)
+	ctx := goldmarkparser.NewContext(goldmarkparser.WithIDs(headingIDs{anchors: make(HeadingAnchors)}))
+	return markdown.Parser().Parse(text.NewReader(source), goldmarkparser.WithContext(ctx)), source
+}
+
+// readSectionMarkdown reads a section's title, duration, topics, teaching points and code
+// examples in one walk of its syntax tree, tagging each code block with the ID of its example
+// for rendering.
+//
+// The title is the first level 1 or 2 heading, and the duration the rest of the first paragraph
+// line starting with **Duration:**. Topics and teaching points are the list items under the first
+// heading or bold label that names them, such as "Topics to cover:" and "Key teaching points:".
+// Fenced code blocks with a language are code examples, wherever they are, including in lists.
+// Examples without an id attribute are numbered in order under idPrefix, e.g. variables-1.
+//
+// Positions are in the file the Markdown comes from, which has lineOffset lines before it.
+func readSectionMarkdown(doc ast.Node, source []byte, lineOffset int, idPrefix string) sectionMarkdown {
+	r := sectionReader{
+		source:   source,
+		lines:    newLineIndex(source, lineOffset),
+		idPrefix: idPrefix,
+		seen:     map[sectionList]bool{},
+	}
+	// Walk only fails when the walker does
+	_ = ast.Walk(doc, r.visit)
+	return r.section
+}
+
+// sectionReader collects a section's elements as readSectionMarkdown walks its syntax tree
+type sectionReader struct {
+	source     []byte
+	lines      lineIndex
+	idPrefix   string // ID the section's examples are numbered under
+	section    sectionMarkdown
+	collecting sectionList          // List the items under the current heading belong to
+	seen       map[sectionList]bool // Lists already started; only the first heading for each counts
+}
+
+// visit handles a node of the syntax tree
+func (r *sectionReader) visit(n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+
+	switch node := n.(type) {
+	case *ast.Heading:
+		r.heading(node)
+		return ast.WalkSkipChildren, nil
+	case *ast.FencedCodeBlock:
+		r.codeBlock(node)
+		return ast.WalkSkipChildren, nil
+	case *ast.ListItem:
+		r.listItem(node)
+	case *ast.Paragraph, *ast.TextBlock:
+		r.paragraph(node)
+	}
+	return ast.WalkContinue, nil
+}
+
+// heading takes the title from the first level 1 or 2 heading, and starts or ends collecting
+// topics and teaching points
+func (r *sectionReader) heading(h *ast.Heading) {
+	text, pos := r.text(h)
+	if r.section.title == "" && h.Level <= maxTitleLevel && text != "" {
+		r.section.title = text
+		r.section.positions.Title = &pos
+	}
+
+	r.collecting = listNone
+	r.startList(text)
+}
+
+// startList starts collecting topics or teaching points when a heading or label names them.
+// Only the first heading or label for each list counts.
+func (r *sectionReader) startList(label string) {
+	name := strings.TrimSuffix(strings.ToLower(label), ":")
+	switch {
+	case slices.Contains(topicsHeadings, name) && !r.seen[listTopics]:
+		r.collecting = listTopics
+	case slices.Contains(teachingPointsHeadings, name) && !r.seen[listTeachingPoints]:
+		r.collecting = listTeachingPoints
+	default:
+		return
+	}
+	r.seen[r.collecting] = true
+}
+
+// listItem adds an item under a topics or teaching points heading. Items of nested lists are
+// added too, after the item they are nested in.
+func (r *sectionReader) listItem(item *ast.ListItem) {
+	if r.collecting == listNone || item.FirstChild() == nil {
+		return
+	}
+	text, pos := r.text(item.FirstChild())
+	if text == "" {
+		return
+	}
+
+	if r.collecting == listTopics {
+		r.section.topics = append(r.section.topics, text)
+		r.section.positions.Topics = append(r.section.positions.Topics, pos)
+	} else {
+		r.section.teachingPoints = append(r.section.teachingPoints, text)
+		r.section.positions.TeachingPoints = append(r.section.positions.TeachingPoints, pos)
+	}
+}
+
+// paragraph takes the duration from the first paragraph line starting with **Duration:**. A
+// paragraph of just a bold label, such as "**Topics to cover:**", starts or ends a list like a heading.
+func (r *sectionReader) paragraph(n ast.Node) {
+	if n.Lines().Len() == 0 {
+		return
+	}
+	// Bold text in a list item is part of the list
+	text, _ := r.text(n)
+	if n.Parent().Kind() == ast.KindDocument && strings.HasPrefix(text, "**") && strings.HasSuffix(text, "**") {
+		r.collecting = listNone
+		r.startList(strings.Trim(text, "*"))
+	}
+	if r.section.duration != "" {
+		return
+	}
+	first := n.Lines().At(0)
+	line := strings.TrimSpace(string(first.Value(r.source)))
+	if duration, ok := strings.CutPrefix(line, durationPrefix); ok {
+		r.section.duration = strings.TrimSpace(duration)
+		pos := r.lines.position(first.Start)
+		r.section.positions.Duration = &pos
+	}
+}
+
+// codeBlock adds the code example of a fenced code block with a language. An output block sets
+// the expected output of the example before it instead.
+func (r *sectionReader) codeBlock(block *ast.FencedCodeBlock) {
+	fenced, ok := readFencedCode(block, r.source, r.lines)
+	if !ok {
+		return
+	}
+
+	// Output blocks belong to the example before them, so only examples are numbered
+	defaultID := fmt.Sprintf("%s-%d", r.idPrefix, len(r.section.examples)+1)
+	example := newCodeExample(fenced.info, fenced.code, defaultID)
+	example.Line, example.Column, example.EndLine = fenced.line, fenced.column, fenced.endLine
+	r.section.examples = appendCodeExample(r.section.examples, example)
+
+	if len(r.section.examples) == 0 {
+		return
+	}
+	last := r.section.examples[len(r.section.examples)-1].ID
+	if example.Language == outputFenceLanguage {
+		block.SetAttributeString(attrOutputFor, []byte(last))
+	} else {
+		block.SetAttributeString(attrExampleID, []byte(last))
+	}
+}
+
+// text returns the text of a block as written, its lines joined by spaces, and where it starts
+func (r *sectionReader) text(n ast.Node) (string, models.SourcePosition) {
+	lines := n.Lines()
+	if lines.Len() == 0 {
+		return "", models.SourcePosition{}
+	}
+
+	parts := make([]string, 0, lines.Len())
+	for i := range lines.Len() {
+		segment := lines.At(i)
+		parts = append(parts, strings.TrimSpace(string(segment.Value(r.source))))
+	}
+	return strings.TrimSpace(strings.Join(parts, " ")), r.lines.position(lines.At(0).Start)
+}
+
+// fencedCode is a fenced code block with a language, and where it is in the source
+type fencedCode struct {
+	info    string // Info string after the opening fence, e.g. "go runnable"
+	code    string
+	line    int // Line of the opening fence
+	column  int // Column of the opening fence
+	endLine int // Line of the closing fence, or of the last line of code if the block is not closed
+}
+
+// readFencedCode reads a fenced code block. It reports false for a block without a language.
+func readFencedCode(block *ast.FencedCodeBlock, source []byte, lines lineIndex) (fencedCode, bool) {
+	if block.Info == nil || len(block.Language(source)) == 0 {
+		return fencedCode{}, false
+	}
+
+	var code strings.Builder
+	for i := range block.Lines().Len() {
+		segment := block.Lines().At(i)
+		code.Write(segment.Value(source))
+	}
+
+	// The info string follows the fence on its line
+	infoStart := block.Info.Segment.Start
+	start := infoStart
+	for start > 0 && (source[start-1] == ' ' || source[start-1] == '\t') {
+		start--
+	}
+	for start > 0 && (source[start-1] == '`' || source[start-1] == '~') {
+		start--
+	}
+	fence := lines.position(start)
+
+	endLine := fence.Line + block.Lines().Len()
+	if closing := strings.TrimSpace(lines.text(source, endLine+1)); closing != "" && strings.Trim(closing, "`~") == "" {
+		endLine++
+	}
+
+	return fencedCode{
+		info:    string(block.Info.Segment.Value(source)),
+		code:    strings.TrimSpace(code.String()),
+		line:    fence.Line,
+		column:  fence.Column,
+		endLine: endLine,
+	}, true
+}
+
+// extractCodeExamples returns the code examples in Markdown that has lineOffset lines of its
+// file before it, found and numbered under idPrefix as ParseSectionFile finds them
+func extractCodeExamples(content string, lineOffset int, idPrefix string) []models.CodeExample {
+	doc, source := parseMarkdown(content)
+	return readSectionMarkdown(doc, source, lineOffset, idPrefix).examples
+}
+
+// lineIndex finds the positions of offsets in a source by the offsets its lines start at
+type lineIndex struct {
+	starts []int
+	offset int // Lines of the file before the source
+}
+
+// newLineIndex indexes the lines of a source that has offset lines of its file before it
+func newLineIndex(source []byte, offset int) lineIndex {
+	starts := []int{0}
+	for i, b := range source {
+		if b == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+	return lineIndex{starts: starts, offset: offset}
+}
+
+// position returns the line in the file and byte column of an offset in the source, both 1-based
+func (li lineIndex) position(offset int) models.SourcePosition {
+	// The first line starting after the offset is the line after the offset's
+	line := sort.Search(len(li.starts), func(i int) bool { return li.starts[i] > offset })
+	return models.SourcePosition{Line: line + li.offset, Column: offset - li.starts[line-1] + 1}
+}
+
+// start returns the offset in the source of the start of a line of the file, or the end of the
+// source after its last line
+func (li lineIndex) start(source []byte, line int) int {
+	line -= li.offset
+	if line > len(li.starts) {
+		return len(source)
+	}
+	return li.starts[max(line, 1)-1]
+}
+
+// text returns a line of the file without its line break, or "" outside the source
+func (li lineIndex) text(source []byte, line int) string {
+	line -= li.offset
+	if line < 1 || line > len(li.starts) {
+		return ""
+	}
+	end := len(source)
+	if line < len(li.starts) {
+		end = li.starts[line] - 1
+	}
+	return string(source[li.starts[line-1]:end])
+}
@@ -2,166 +2,100 @@ package parser
 
 import (
 	"fmt"
+	"os"
 	"regexp"
 	"strings"
 
 	"github.com/jonesrussell/go-fundamentals-best-practices/pkg/models"
+	"github.com/yuin/goldmark/ast"
 )
 
-// sectionHeaderPrefix is the markdown prefix for section headers.
-const sectionHeaderPrefix = "###"
+// sectionHeadingLevel is the level of the headings that start the sections of a single-file tutorial
+const sectionHeadingLevel = 3
 
-// isSectionHeader checks if a line is a valid section header.
-func isSectionHeader(trimmed string) bool {
-	return strings.HasPrefix(trimmed, sectionHeaderPrefix) && !strings.Contains(trimmed, "Video")
-}
+// sectionNumberRegex matches the number before a section heading's title, e.g. "1. "
+var sectionNumberRegex = regexp.MustCompile(`^\d+\.\s*`)
 
-// extractTitleFromSectionHeader extracts and cleans the title from a section header line.
-func extractTitleFromSectionHeader(trimmed string) string {
-	title := strings.TrimPrefix(trimmed, sectionHeaderPrefix)
-	title = strings.TrimSpace(title)
-	// Remove markdown bold formatting
-	title = strings.TrimPrefix(title, "**")
-	title = strings.TrimSuffix(title, "**")
-	// Remove number prefix if present
-	title = regexp.MustCompile(`^\d+\.\s*`).ReplaceAllString(title, "")
-	return title
+// sectionHeading is a heading that starts a section of a single-file tutorial
+type sectionHeading struct {
+	title string
+	line  int // Line of the heading, 1-based
 }
 
-// createNewSection creates a new section with the given order and title.
-func createNewSection(order int, title string) models.Section {
-	return models.Section{
-		ID:             fmt.Sprintf("section-%d", order),
-		Title:          title,
-		Order:          order,
-		Topics:         []string{},
-		CodeExamples:   []models.CodeExample{},
-		TeachingPoints: []string{},
-		Content:        "",
-	}
-}
-
-// parseListItems extracts list items from lines starting at the given index.
-// It stops when encountering an empty line or a line starting with specific prefixes.
-func parseListItems(lines []string, startIndex int, stopPrefixes []string) []string {
-	var items []string
-	for j := startIndex; j < len(lines); j++ {
-		itemLine := strings.TrimSpace(lines[j])
-		if itemLine == "" || shouldStopParsing(itemLine, stopPrefixes) {
-			break
-		}
-		if strings.HasPrefix(itemLine, "-") {
-			item := strings.TrimPrefix(itemLine, "-")
-			item = strings.TrimSpace(item)
-			item = strings.Trim(item, "`")
-			if item != "" {
-				items = append(items, item)
-			}
-		}
-	}
-	return items
-}
-
-// shouldStopParsing checks if parsing should stop based on line prefixes.
-func shouldStopParsing(line string, stopPrefixes []string) bool {
-	for _, prefix := range stopPrefixes {
-		if strings.HasPrefix(line, prefix) {
-			return true
+// parseSections splits a single-file tutorial into the sections its ### headings start, and reads
+// each one with the same walk of its syntax tree as a section file
+func (p *TutorialParser) parseSections(content string) []models.Section {
+	doc, source := parseMarkdown(content)
+	lines := newLineIndex(source, 0)
+	headings := findSectionHeadings(doc, source, lines)
+
+	sections := make([]models.Section, 0, len(headings))
+	for i, heading := range headings {
+		// A section is the lines after its heading, up to the next heading
+		end := len(source)
+		if i+1 < len(headings) {
+			end = lines.start(source, headings[i+1].line)
 		}
+		body := string(source[lines.start(source, heading.line+1):end])
+		sections = append(sections, readSection(i+1, heading, body))
 	}
-	return false
-}
 
-// isTopicsLine checks if the line indicates a topics section.
-func isTopicsLine(trimmed string) bool {
-	return strings.Contains(trimmed, "Topics to cover:") || strings.Contains(trimmed, "Topics:")
-}
-
-// isTeachingPointsLine checks if the line indicates a teaching points section.
-func isTeachingPointsLine(trimmed string) bool {
-	return strings.Contains(trimmed, "Key teaching points:") || strings.Contains(trimmed, "Teaching points:")
+	return sections
 }
 
-// parseSections extracts all sections from markdown content
-func (p *TutorialParser) parseSections(content string) []models.Section {
-	var sections []models.Section
-
-	lines := strings.Split(content, "\n")
-	currentSection := models.Section{Order: 0}
-	sectionOrder := 0
-	inSection := false
-
-	topicStopPrefixes := []string{"**", sectionHeaderPrefix}
-	teachingStopPrefixes := []string{"**", sectionHeaderPrefix, "---"}
-
-	for i, line := range lines {
-		trimmed := strings.TrimSpace(line)
-
-		if isSectionHeader(trimmed) {
-			sections, currentSection, sectionOrder, inSection = p.handleSectionHeader(
-				sections, currentSection, sectionOrder, inSection, trimmed,
-			)
+// findSectionHeadings returns the top-level ### headings of a single-file tutorial, except those
+// of videos
+func findSectionHeadings(doc ast.Node, source []byte, lines lineIndex) []sectionHeading {
+	var headings []sectionHeading
+	for n := doc.FirstChild(); n != nil; n = n.NextSibling() {
+		h, ok := n.(*ast.Heading)
+		if !ok || h.Level != sectionHeadingLevel || h.Lines().Len() == 0 {
 			continue
 		}
-
-		if !inSection {
+		segment := h.Lines().At(0)
+		text := strings.TrimSpace(string(segment.Value(source)))
+		if strings.Contains(text, "Video") {
 			continue
 		}
-
-		currentSection.Content += line + "\n"
-		p.parseSectionContent(&currentSection, lines, i, trimmed, topicStopPrefixes, teachingStopPrefixes)
-	}
-
-	// Add last section
-	if inSection && currentSection.Title != "" {
-		sections = append(sections, currentSection)
+		headings = append(headings, sectionHeading{
+			title: sectionTitle(text),
+			line:  lines.position(segment.Start).Line,
+		})
 	}
-
-	return sections
+	return headings
 }
 
-// handleSectionHeader processes a section header line and returns updated state.
-func (p *TutorialParser) handleSectionHeader(
-	sections []models.Section,
-	currentSection models.Section,
-	sectionOrder int,
-	inSection bool,
-	trimmed string,
-) (updatedSections []models.Section, newSection models.Section, newOrder int, nowInSection bool) {
-	// Save previous section if it exists
-	if inSection && currentSection.Title != "" {
-		sections = append(sections, currentSection)
-	}
+// sectionTitle cleans a section heading's text into its title, removing bold and the section number
+func sectionTitle(text string) string {
+	title := strings.TrimSuffix(strings.TrimPrefix(text, "**"), "**")
+	return sectionNumberRegex.ReplaceAllString(title, "")
+}
 
-	// Start new section
-	sectionOrder++
-	title := extractTitleFromSectionHeader(trimmed)
-	newSection = createNewSection(sectionOrder, title)
+// readSection reads a section of a single-file tutorial from the Markdown after its heading
+func readSection(order int, heading sectionHeading, content string) models.Section {
+	doc, source := parseMarkdown(content)
+	md := readSectionMarkdown(doc, source, heading.line, fmt.Sprintf("section-%d", order))
 
-	return sections, newSection, sectionOrder, true
-}
+	positions := md.positions
+	positions.Title = &models.SourcePosition{Line: heading.line, Column: 1}
 
-// parseSectionContent parses content within a section (topics, code, teaching points).
-func (p *TutorialParser) parseSectionContent(
-	section *models.Section,
-	lines []string,
-	lineIndex int,
-	trimmed string,
-	topicStopPrefixes, teachingStopPrefixes []string,
-) {
-	if isTopicsLine(trimmed) {
-		topics := parseListItems(lines, lineIndex+1, topicStopPrefixes)
-		section.Topics = append(section.Topics, topics...)
+	section := models.Section{
+		ID:             fmt.Sprintf("section-%d", order),
+		Title:          heading.title,
+		Order:          order,
+		Duration:       md.duration,
+		Topics:         md.topics,
+		CodeExamples:   md.examples,
+		TeachingPoints: md.teachingPoints,
+		Content:        content,
+		Positions:      &positions,
 	}
 
-	if strings.HasPrefix(trimmed, "```") {
-		if codeExample := p.parseCodeBlock(lines, lineIndex); codeExample != nil {
-			section.CodeExamples = append(section.CodeExamples, *codeExample)
-		}
+	// The walk tagged the code blocks with their examples' IDs for rendering
+	var err error
+	if section.ContentHTML, err = renderDocument(doc, source); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: section %s: %v\n", heading.title, err)
 	}
 
-	if isTeachingPointsLine(trimmed) {
-		points := parseListItems(lines, lineIndex+1, teachingStopPrefixes)
-		section.TeachingPoints = append(section.TeachingPoints, points...)
-	}
+	return section
 }
@@ -31,6 +31,11 @@ func NewTutorialParser(tutorialsDir string) *TutorialParser {
 	}
 }
 
+// TutorialsDir returns the directory tutorials are loaded from
+func (p *TutorialParser) TutorialsDir() string {
+	return p.tutorialsDir
+}
+
 // GetTutorial returns a tutorial by ID, checking directory format first, then file format
 func (p *TutorialParser) GetTutorial(tutorialID string, includeInstructorNotes bool) (*models.Tutorial, error) {
 	// Check if tutorial exists as directory
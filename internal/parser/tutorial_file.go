@@ -37,6 +37,10 @@ func (p *TutorialParser) ParseTutorial(filename string) (*models.Tutorial, error
 
 	// Parse sections
 	sections := p.parseSections(contentStr)
+	for i := range sections {
+		sections[i].Source = filename
+	}
+	loadSectionFixtures(filepath.Join(p.tutorialsDir, fixturesDirName), sections)
 	tutorial.Sections = sections
 
 	return tutorial, nil
@@ -0,0 +1,63 @@
+package search
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/jonesrussell/go-fundamentals-best-practices/internal/parser"
+)
+
+// chunk is the part of a Markdown document under one heading.
+type chunk struct {
+	heading string
+	anchor  string // Anchor of the heading, "" for text before the first heading
+	prose   string
+	code    []string // Bodies of the fenced code blocks
+}
+
+// headingLine matches a Markdown heading, capturing its text without the closing hashes.
+var headingLine = regexp.MustCompile(`^#{1,6}\s+(.*?)(?:\s+#+)?\s*$`)
+
+// splitChunks splits Markdown at its headings. Headings inside fenced code blocks are code.
+func splitChunks(markdown string) []chunk {
+	anchors := make(parser.HeadingAnchors)
+	chunks := []chunk{{}}
+	current := &chunks[0]
+
+	var prose, code strings.Builder
+	fence := ""
+	flush := func() {
+		current.prose = strings.TrimSpace(prose.String())
+		prose.Reset()
+	}
+
+	for line := range strings.SplitSeq(markdown, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case fence != "":
+			if strings.HasPrefix(trimmed, fence) {
+				current.code = append(current.code, strings.TrimSpace(code.String()))
+				code.Reset()
+				fence = ""
+				continue
+			}
+			code.WriteString(line + "\n")
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			fence = trimmed[:3]
+		case headingLine.MatchString(trimmed):
+			flush()
+			text := headingLine.FindStringSubmatch(trimmed)[1]
+			chunks = append(chunks, chunk{heading: text, anchor: anchors.Next(text)})
+			current = &chunks[len(chunks)-1]
+		default:
+			prose.WriteString(line + "\n")
+		}
+	}
+	flush()
+
+	// Drop the empty chunk before a document's first heading
+	if chunks[0].prose == "" && len(chunks[0].code) == 0 && len(chunks) > 1 {
+		chunks = chunks[1:]
+	}
+	return chunks
+}
@@ -0,0 +1,339 @@
+// Package search is an in-memory full-text index of the tutorials and cheatsheets.
+package search
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/jonesrussell/go-fundamentals-best-practices/pkg/models"
+)
+
+// Kinds of search hit.
+const (
+	KindSection    = "section"
+	KindCheatsheet = "cheatsheet"
+)
+
+// Field weights: a term in a heading counts for more than one in the text under it.
+const (
+	weightHeading = 4.0
+	weightTopic   = 3.0
+	weightCode    = 1.5
+	weightProse   = 1.0
+)
+
+// BM25 parameters: termSaturation limits how much repeating a term helps, lengthNormalization
+// how much long documents are penalised, and idfSmoothing keeps rare terms' weight finite.
+const (
+	termSaturation      = 1.2
+	lengthNormalization = 0.75
+	idfSmoothing        = 0.5
+)
+
+// Hit is a search result: a part of a section or cheatsheet, with a link to it.
+type Hit struct {
+	Kind         string  `json:"kind"`
+	TutorialID   string  `json:"tutorialId,omitempty"`
+	SectionID    string  `json:"sectionId,omitempty"`
+	SectionOrder int     `json:"sectionOrder,omitempty"`
+	CheatsheetID string  `json:"cheatsheetId,omitempty"`
+	ExampleID    string  `json:"exampleId,omitempty"` // Set when the snippet comes from a code example
+	Title        string  `json:"title"`               // Tutorial or cheatsheet title
+	SectionTitle string  `json:"sectionTitle,omitempty"`
+	Heading      string  `json:"heading,omitempty"`
+	Anchor       string  `json:"anchor,omitempty"`
+	Link         string  `json:"link"` // App path to the hit, e.g. "/tutorial/7/section/5#syncwaitgroup"
+	Snippet      string  `json:"snippet"`
+	Score        float64 `json:"score"`
+}
+
+// document is an indexed chunk together with what a hit on it shows.
+type document struct {
+	hit        Hit
+	chunk      chunk
+	exampleIDs []string // Example ID of each code block in a section chunk, "" when it is not an example
+	length     float64  // Weighted number of terms
+}
+
+// posting records how often, by weight, a term appears in a document.
+type posting struct {
+	doc    int
+	weight float64
+}
+
+// Index is an inverted index of tutorial sections and cheatsheets, split at their headings.
+// It is immutable once built, so it can be searched concurrently.
+type Index struct {
+	docs      []document
+	postings  map[string][]posting
+	avgLength float64
+}
+
+// NewIndex indexes the tutorials and cheatsheets.
+func NewIndex(tutorials []*models.Tutorial, cheatsheets []*models.Cheatsheet) *Index {
+	idx := &Index{postings: make(map[string][]posting)}
+
+	for _, t := range tutorials {
+		for i := range t.Sections {
+			idx.addSection(t, &t.Sections[i])
+		}
+	}
+	for _, sheet := range cheatsheets {
+		for i := range sheet.Entries {
+			idx.addCheatsheetEntry(sheet, &sheet.Entries[i])
+		}
+	}
+
+	total := 0.0
+	for _, doc := range idx.docs {
+		total += doc.length
+	}
+	if len(idx.docs) > 0 {
+		idx.avgLength = total / float64(len(idx.docs))
+	}
+	return idx
+}
+
+// addSection indexes the chunks of a section. The section's topics, tags and objectives are
+// indexed with its first chunk.
+func (idx *Index) addSection(t *models.Tutorial, section *models.Section) {
+	topics := slices.Concat(section.Topics, section.Tags, section.Objectives)
+
+	for i, c := range splitChunks(section.Content) {
+		doc := document{
+			hit: Hit{
+				Kind:         KindSection,
+				TutorialID:   t.ID,
+				SectionID:    section.ID,
+				SectionOrder: section.Order,
+				Title:        t.Title,
+				SectionTitle: section.Title,
+				Heading:      c.heading,
+				Anchor:       c.anchor,
+				Link:         link(fmt.Sprintf("/tutorial/%s/section/%d", t.ID, section.Order), c.anchor),
+			},
+			chunk: c,
+		}
+		for _, code := range c.code {
+			doc.exampleIDs = append(doc.exampleIDs, exampleID(section.CodeExamples, code))
+		}
+
+		var extra []string
+		if i == 0 {
+			extra = topics
+		}
+		idx.add(doc, extra)
+	}
+}
+
+// addCheatsheetEntry indexes a cheatsheet entry as one document, with the heading it is
+// grouped under as a topic.
+func (idx *Index) addCheatsheetEntry(sheet *models.Cheatsheet, entry *models.CheatsheetEntry) {
+	doc := document{
+		hit: Hit{
+			Kind:         KindCheatsheet,
+			CheatsheetID: sheet.ID,
+			Title:        sheet.Title,
+			Heading:      entry.Title,
+			Anchor:       entry.ID,
+			Link:         link("/cheatsheets/"+sheet.ID, entry.ID),
+		},
+		chunk: chunk{heading: entry.Title, anchor: entry.ID},
+	}
+
+	// Headings within the entry are read as prose
+	var prose []string
+	for _, c := range splitChunks(entry.Content) {
+		prose = append(prose, c.heading, c.prose)
+		doc.chunk.code = append(doc.chunk.code, c.code...)
+	}
+	doc.chunk.prose = strings.TrimSpace(strings.Join(prose, "\n"))
+	for _, code := range doc.chunk.code {
+		doc.exampleIDs = append(doc.exampleIDs, exampleID(entry.CodeExamples, code))
+	}
+
+	var topics []string
+	if entry.Group != "" {
+		topics = []string{entry.Group}
+	}
+	idx.add(doc, topics)
+}
+
+// exampleID returns the ID of the code example with the given code, or "".
+func exampleID(examples []models.CodeExample, code string) string {
+	for _, example := range examples {
+		if example.Code == code {
+			return example.ID
+		}
+	}
+	return ""
+}
+
+// link returns an app path with an optional anchor.
+func link(path, anchor string) string {
+	if anchor == "" {
+		return path
+	}
+	return path + "#" + anchor
+}
+
+// add indexes a document's heading, prose, code and any topics.
+func (idx *Index) add(doc document, topics []string) {
+	weights := make(map[string]float64)
+	count := func(text string, weight float64) {
+		for _, term := range tokenize(text) {
+			weights[term] += weight
+			doc.length += weight
+		}
+	}
+
+	count(doc.chunk.heading, weightHeading)
+	count(doc.chunk.prose, weightProse)
+	for _, code := range doc.chunk.code {
+		count(code, weightCode)
+	}
+	for _, topic := range topics {
+		count(topic, weightTopic)
+	}
+	if len(weights) == 0 {
+		return
+	}
+
+	id := len(idx.docs)
+	idx.docs = append(idx.docs, doc)
+	for term, weight := range weights {
+		idx.postings[term] = append(idx.postings[term], posting{doc: id, weight: weight})
+	}
+}
+
+// Search returns up to limit hits for a query, best first. Matching more of the query's terms
+// counts for more than matching one term often.
+func (idx *Index) Search(query string, limit int) []Hit {
+	terms := slices.Compact(slices.Sorted(slices.Values(tokenize(query))))
+	if len(terms) == 0 {
+		return nil
+	}
+
+	scores := make(map[int]float64)
+	matched := make(map[int]int)
+	n := float64(len(idx.docs))
+	for _, term := range terms {
+		postings := idx.postings[term]
+		df := float64(len(postings))
+		idf := math.Log(1 + (n-df+idfSmoothing)/(df+idfSmoothing))
+		for _, p := range postings {
+			relativeLength := idx.docs[p.doc].length / idx.avgLength
+			norm := termSaturation * (1 - lengthNormalization + lengthNormalization*relativeLength)
+			scores[p.doc] += idf * p.weight * (termSaturation + 1) / (p.weight + norm)
+			matched[p.doc]++
+		}
+	}
+
+	ranked := make([]int, 0, len(scores))
+	for doc, score := range scores {
+		coverage := float64(matched[doc]) / float64(len(terms))
+		scores[doc] = score * coverage * coverage
+		ranked = append(ranked, doc)
+	}
+	slices.SortFunc(ranked, func(a, b int) int {
+		return cmp.Or(cmp.Compare(scores[b], scores[a]), cmp.Compare(a, b))
+	})
+
+	hits := make([]Hit, 0, min(limit, len(ranked)))
+	for _, doc := range ranked[:min(limit, len(ranked))] {
+		hit := idx.docs[doc].hit
+		hit.Score = math.Round(scores[doc]*scoreScale) / scoreScale
+		hit.Snippet, hit.ExampleID = idx.docs[doc].snippet(terms)
+		hits = append(hits, hit)
+	}
+	return hits
+}
+
+// scoreScale rounds scores to a few decimal places for display.
+const scoreScale = 1000
+
+// snippet returns an excerpt of the document around the first query term found in it,
+// looking at the prose before the code, and the ID of the code example it came from.
+func (doc *document) snippet(terms []string) (string, string) {
+	// Longer terms first: "sync.waitgroup" makes a better excerpt than "sync"
+	byLength := slices.Clone(terms)
+	slices.SortStableFunc(byLength, func(a, b string) int { return cmp.Compare(len(b), len(a)) })
+
+	prose := cleanProse(doc.chunk.prose)
+	for _, term := range byLength {
+		if excerpt, ok := excerptAround(prose, term); ok {
+			return excerpt, ""
+		}
+	}
+	for _, term := range byLength {
+		for i, code := range doc.chunk.code {
+			excerpt, ok := excerptAround(code, term)
+			if !ok {
+				continue
+			}
+			id := ""
+			if i < len(doc.exampleIDs) {
+				id = doc.exampleIDs[i]
+			}
+			return excerpt, id
+		}
+	}
+
+	excerpt, _ := excerptAround(prose, "")
+	return excerpt, ""
+}
+
+// Snippet window around a match, in bytes.
+const (
+	snippetBefore = 60
+	snippetAfter  = 140
+)
+
+// excerptAround returns the text around the first case-insensitive match of term, with
+// whitespace collapsed. An empty term matches the start of the text.
+func excerptAround(text, term string) (string, bool) {
+	i := strings.Index(strings.ToLower(text), term)
+	if i < 0 {
+		return "", false
+	}
+
+	start := max(0, i-snippetBefore)
+	end := min(len(text), i+len(term)+snippetAfter)
+	// Widen to whole words, and so to whole UTF-8 characters
+	for start > 0 && !isSpace(text[start-1]) {
+		start--
+	}
+	for end < len(text) && !isSpace(text[end]) {
+		end++
+	}
+
+	excerpt := strings.Join(strings.Fields(text[start:end]), " ")
+	if start > 0 {
+		excerpt = "…" + excerpt
+	}
+	if end < len(text) {
+		excerpt += "…"
+	}
+	return excerpt, true
+}
+
+var (
+	// markdownLinkText matches a link or image, capturing the text shown.
+	markdownLinkText = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+	// emphasis matches bold and italic markers.
+	emphasis = regexp.MustCompile(`\*{1,3}|_{2,3}`)
+)
+
+// cleanProse strips link targets and emphasis from Markdown so snippets read as text.
+func cleanProse(markdown string) string {
+	return emphasis.ReplaceAllString(markdownLinkText.ReplaceAllString(markdown, "$1"), "")
+}
+
+// isSpace reports whether b is an ASCII whitespace byte.
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\n' || b == '\t' || b == '\r'
+}
@@ -0,0 +1,132 @@
+package search
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// operators are the Go operators worth searching for, longest first so ":=" is not read as ":".
+var operators = []string{
+	"...", "<<=", ">>=", "&^=",
+	":=", "<-", "&&", "||", "==", "!=", "<=", ">=", "++", "--", "<<", ">>", "&^",
+	"+=", "-=", "*=", "/=", "%=", "&=", "|=", "^=",
+}
+
+// stopWords are common English words left out of the index. Words that are also Go
+// keywords, such as "for" and "if", are kept.
+var stopWords = []string{"a", "an", "and", "are", "as", "be", "by", "it", "of", "that", "the", "this", "to", "is"}
+
+// tokenize splits text into search terms. Go identifiers are kept whole, including qualified
+// ones such as "sync.WaitGroup", and also split into their parts ("sync", "waitgroup") and the
+// words of camel case names ("wait", "group"), so any of them finds the text. Operators such as
+// ":=" are terms too. Terms are lower case.
+func tokenize(text string) []string {
+	var terms []string
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if isIdentRune(r) {
+			ident := identifierAt(text[i:])
+			terms = appendIdentifier(terms, ident)
+			i += len(ident)
+			continue
+		}
+		if op := operatorAt(text[i:]); op != "" {
+			terms = append(terms, op)
+			i += len(op)
+			continue
+		}
+		i += size
+	}
+	return terms
+}
+
+// isIdentRune reports whether r can appear in a Go identifier.
+func isIdentRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// identifierAt returns the identifier, possibly qualified, at the start of text.
+func identifierAt(text string) string {
+	end := 0
+	for end < len(text) {
+		r, size := utf8.DecodeRuneInString(text[end:])
+		if isIdentRune(r) {
+			end += size
+			continue
+		}
+		// A dot joins two identifiers, as in sync.WaitGroup, but not a sentence to the next
+		next, _ := utf8.DecodeRuneInString(text[min(end+size, len(text)):])
+		if r == '.' && end+size < len(text) && unicode.IsLetter(next) {
+			end += size
+			continue
+		}
+		break
+	}
+	return text[:end]
+}
+
+// operatorAt returns the operator at the start of text, or "".
+func operatorAt(text string) string {
+	for _, op := range operators {
+		if strings.HasPrefix(text, op) {
+			return op
+		}
+	}
+	return ""
+}
+
+// appendIdentifier appends the terms for an identifier: the whole of it, its parts and
+// their camel case words.
+func appendIdentifier(terms []string, ident string) []string {
+	lower := strings.ToLower(ident)
+	parts := strings.Split(ident, ".")
+	if len(parts) == 1 && slices.Contains(stopWords, lower) {
+		return terms
+	}
+	if len(parts) > 1 {
+		terms = append(terms, lower)
+	}
+
+	for _, part := range parts {
+		terms = append(terms, strings.ToLower(part))
+		if words := camelWords(part); len(words) > 1 {
+			terms = append(terms, words...)
+		}
+	}
+	return terms
+}
+
+// camelWords splits an identifier into its lower case words, e.g. "parseHTTPRequest"
+// into "parse", "http" and "request", and "max_value" into "max" and "value".
+func camelWords(ident string) []string {
+	var words []string
+	runes := []rune(ident)
+	start := 0
+
+	flush := func(end int) {
+		if end > start {
+			words = append(words, strings.ToLower(string(runes[start:end])))
+		}
+	}
+
+	for i, r := range runes {
+		switch {
+		case r == '_':
+			flush(i)
+			start = i + 1
+		case i > start && unicode.IsUpper(r):
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			// A new word starts at an upper case letter after a lower case one ("parseHTTP"),
+			// or at the last upper case letter before a lower case one ("HTTPRequest")
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				flush(i)
+				start = i
+			}
+		}
+	}
+	flush(len(runes))
+	return words
+}
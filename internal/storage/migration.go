@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/jonesrussell/go-fundamentals-best-practices/pkg/models"
+)
+
+// SectionIDMigration maps old positional section IDs such as "section-3" to stable IDs such
+// as "variables", per tutorial ID
+type SectionIDMigration map[string]map[string]string
+
+// LoadSectionIDMigration reads a recorded section ID mapping. A missing file is an empty mapping.
+func LoadSectionIDMigration(path string) (SectionIDMigration, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return SectionIDMigration{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read section ID migration: %w", err)
+	}
+
+	var migration SectionIDMigration
+	if err := json.Unmarshal(data, &migration); err != nil {
+		return nil, fmt.Errorf("failed to decode section ID migration: %w", err)
+	}
+	return migration, nil
+}
+
+// sectionID returns the stable ID for a section ID, which is the ID itself unless it is mapped
+func (m SectionIDMigration) sectionID(tutorialID, sectionID string) string {
+	if newID, ok := m[tutorialID][sectionID]; ok {
+		return newID
+	}
+	return sectionID
+}
+
+// migrateProgress rewrites the section IDs in a user's progress and reports whether any changed
+func (m SectionIDMigration) migrateProgress(progress *models.Progress) bool {
+	changed := false
+
+	for tutorialID, sectionIDs := range progress.CompletedSections {
+		migrated := make([]string, 0, len(sectionIDs))
+		for _, id := range sectionIDs {
+			newID := m.sectionID(tutorialID, id)
+			changed = changed || newID != id
+			if !slices.Contains(migrated, newID) {
+				migrated = append(migrated, newID)
+			}
+		}
+		progress.CompletedSections[tutorialID] = migrated
+	}
+
+	if newID := m.sectionID(progress.CurrentTutorial, progress.CurrentSection); newID != progress.CurrentSection {
+		progress.CurrentSection = newID
+		changed = true
+	}
+
+	return changed
+}
@@ -12,9 +12,10 @@ import (
 
 // ProgressStorage handles storage of user progress
 type ProgressStorage struct {
-	mu       sync.RWMutex
-	progress map[string]*models.Progress // userID -> progress
-	filePath string
+	mu        sync.RWMutex
+	progress  map[string]*models.Progress // userID -> progress
+	filePath  string
+	migration SectionIDMigration // Rewrites old section IDs sent by clients
 }
 
 // NewProgressStorage creates a new progress storage
@@ -54,6 +55,27 @@ func (s *ProgressStorage) ensureUserProgress(userID string) *models.Progress {
 	return s.progress[userID]
 }
 
+// MigrateSectionIDs rewrites stored progress from old section IDs, and keeps the mapping to
+// rewrite progress from clients that still send them. It returns the number of users migrated.
+func (s *ProgressStorage) MigrateSectionIDs(migration SectionIDMigration) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.migration = migration
+
+	migrated := 0
+	for _, progress := range s.progress {
+		if migration.migrateProgress(progress) {
+			migrated++
+		}
+	}
+	if migrated == 0 {
+		return 0, nil
+	}
+
+	return migrated, s.save()
+}
+
 // GetProgress retrieves progress for a user
 func (s *ProgressStorage) GetProgress(userID string) *models.Progress {
 	s.mu.RLock()
@@ -73,6 +95,7 @@ func (s *ProgressStorage) UpdateProgress(userID string, progress *models.Progres
 	defer s.mu.Unlock()
 
 	progress.UserID = userID
+	s.migration.migrateProgress(progress)
 	s.progress[userID] = progress
 
 	return s.save()
@@ -97,6 +120,7 @@ func (s *ProgressStorage) MarkSectionComplete(userID, tutorialID, sectionID stri
 	if progress.CompletedSections == nil {
 		progress.CompletedSections = make(map[string][]string)
 	}
+	sectionID = s.migration.sectionID(tutorialID, sectionID)
 
 	// Check if already completed
 	if containsID(progress.CompletedSections[tutorialID], sectionID) {
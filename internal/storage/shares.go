@@ -0,0 +1,230 @@
+package storage
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// shareIDLength is the number of base64url characters of the content hash used as a share ID
+const shareIDLength = 11
+
+// sharesDirName is the directory under the data directory that holds shared snippets
+const sharesDirName = "shares"
+
+// shareTempPattern names the temporary files shares are written to before being renamed into place
+const shareTempPattern = ".share-*.tmp"
+
+// ErrShareNotFound is returned when a share does not exist or has expired
+var ErrShareNotFound = errors.New("share not found")
+
+// shareIDPattern matches well-formed share IDs, which keeps IDs from escaping the shares directory
+var shareIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+
+// Share is code saved under a permalink together with the options to run it
+type Share struct {
+	ID        string          `json:"id"`
+	Code      string          `json:"code"`
+	Options   json.RawMessage `json:"options,omitempty"`
+	CreatedAt time.Time       `json:"createdAt"`
+	ExpiresAt *time.Time      `json:"expiresAt,omitempty"`
+}
+
+// expired reports whether the share has expired at now
+func (s *Share) expired(now time.Time) bool {
+	return s.ExpiresAt != nil && !now.Before(*s.ExpiresAt)
+}
+
+// ShareStorage stores shared snippets as one file per share. IDs are derived from the
+// content, so sharing the same code and options twice returns the same link.
+type ShareStorage struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// NewShareStorage creates a share storage and removes shares that have expired
+func NewShareStorage(dataDir string) (*ShareStorage, error) {
+	dir := filepath.Join(dataDir, sharesDirName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create shares directory: %w", err)
+	}
+
+	storage := &ShareStorage{dir: dir}
+	if err := storage.Prune(); err != nil {
+		return nil, fmt.Errorf("failed to remove expired shares: %w", err)
+	}
+
+	return storage, nil
+}
+
+// Save stores code and its options, returning the share. A ttl of zero keeps the share
+// forever. Saving content that is already shared keeps the longer of the two lifetimes.
+func (s *ShareStorage) Save(code string, options json.RawMessage, ttl time.Duration) (*Share, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC()
+	share := &Share{
+		ID:        shareID(code, options),
+		Code:      code,
+		Options:   options,
+		CreatedAt: now,
+	}
+	if ttl > 0 {
+		expiresAt := now.Add(ttl)
+		share.ExpiresAt = &expiresAt
+	}
+
+	existing, err := s.load(share.ID)
+	switch {
+	case errors.Is(err, ErrShareNotFound):
+		// First time this content is shared
+	case err != nil:
+		return nil, err
+	case existing.Code != code || string(existing.Options) != string(options):
+		return nil, fmt.Errorf("share ID collision for %s", share.ID)
+	case existing.expired(now):
+		// Shared again after expiring, so it starts over
+	default:
+		share.CreatedAt = existing.CreatedAt
+		if existing.ExpiresAt == nil || (share.ExpiresAt != nil && existing.ExpiresAt.After(*share.ExpiresAt)) {
+			share.ExpiresAt = existing.ExpiresAt
+		}
+	}
+
+	if err := s.save(share); err != nil {
+		return nil, err
+	}
+	return share, nil
+}
+
+// Get returns a share by ID, removing it if it has expired
+func (s *ShareStorage) Get(id string) (*Share, error) {
+	if !shareIDPattern.MatchString(id) {
+		return nil, ErrShareNotFound
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	share, err := s.load(id)
+	if err != nil {
+		return nil, err
+	}
+	if share.expired(time.Now()) {
+		if removeErr := os.Remove(s.path(id)); removeErr != nil && !os.IsNotExist(removeErr) {
+			return nil, removeErr
+		}
+		return nil, ErrShareNotFound
+	}
+
+	return share, nil
+}
+
+// shareID hashes the code and options into a short URL-safe ID
+func shareID(code string, options json.RawMessage) string {
+	h := sha256.New()
+	h.Write([]byte(code))
+	h.Write([]byte{0})
+	h.Write(options)
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))[:shareIDLength]
+}
+
+// path returns the file a share is stored in
+func (s *ShareStorage) path(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
+
+// load reads a share from disk
+func (s *ShareStorage) load(id string) (*Share, error) {
+	data, err := os.ReadFile(s.path(id))
+	if os.IsNotExist(err) {
+		return nil, ErrShareNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var share Share
+	if err := json.Unmarshal(data, &share); err != nil {
+		return nil, fmt.Errorf("failed to decode share %s: %w", id, err)
+	}
+	return &share, nil
+}
+
+// save writes a share to disk. The share is written to a temporary file that is renamed into
+// place, so a crash or a full disk never leaves a truncated share behind.
+func (s *ShareStorage) save(share *Share) error {
+	data, err := json.Marshal(share)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(s.dir, shareTempPattern)
+	if err != nil {
+		return fmt.Errorf("failed to create share file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // Fails harmlessly once the file is renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write share %s: %w", share.ID, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write share %s: %w", share.ID, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write share %s: %w", share.ID, err)
+	}
+
+	return os.Rename(tmp.Name(), s.path(share.ID))
+}
+
+// Prune removes expired shares, and temporary files left by saves that were interrupted
+func (s *ShareStorage) Prune() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.prune(time.Now())
+}
+
+// prune removes expired shares and leftover temporary files. Must be called with the lock
+// held, or before the storage is shared.
+func (s *ShareStorage) prune(now time.Time) error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if temp, _ := filepath.Match(shareTempPattern, entry.Name()); temp {
+			if removeErr := os.Remove(filepath.Join(s.dir, entry.Name())); removeErr != nil && !os.IsNotExist(removeErr) {
+				return removeErr
+			}
+			continue
+		}
+
+		id, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok || !shareIDPattern.MatchString(id) {
+			continue
+		}
+		share, loadErr := s.load(id)
+		if loadErr != nil || !share.expired(now) {
+			continue
+		}
+		if removeErr := os.Remove(s.path(id)); removeErr != nil {
+			return removeErr
+		}
+	}
+
+	return nil
+}
@@ -6,7 +6,8 @@ type Tutorial struct {
 	Title           string    `json:"title"`
 	Duration        string    `json:"duration"`
 	Difficulty      string    `json:"difficulty"`
-	Prerequisites   []string  `json:"prerequisites"`
+	Prerequisites   []string  `json:"prerequisites"`               // Free-text prerequisites
+	RequiredIDs     []string  `json:"requiredTutorials,omitempty"` // IDs of tutorials to complete first
 	Sections        []Section `json:"sections"`
 	Level           string    `json:"level"` // Beginner, Intermediate, Advanced
 	TableOfContents string    `json:"tableOfContents,omitempty"`
@@ -18,7 +19,8 @@ type TutorialMetadata struct {
 	Title         string   `json:"title"`
 	Duration      string   `json:"duration"`
 	Difficulty    string   `json:"difficulty"`
-	Prerequisites []string `json:"prerequisites"`
+	Prerequisites []string `json:"prerequisites"`               // Free-text prerequisites
+	RequiredIDs   []string `json:"requiredTutorials,omitempty"` // IDs of tutorials to complete first
 	Level         string   `json:"level"`
 	SectionCount  int      `json:"sectionCount"`
 }
@@ -32,18 +34,83 @@ type Section struct {
 	TeachingPoints  []string      `json:"teachingPoints"`
 	Order           int           `json:"order"`
 	Content         string        `json:"content"`                   // Markdown content for the section
+	ContentHTML     string        `json:"contentHtml,omitempty"`     // Content rendered to HTML, code blocks tagged with data-example-id
 	InstructorNotes string        `json:"instructorNotes,omitempty"` // Instructor-only notes (when instructor mode enabled)
+	Source          string        `json:"source,omitempty"`          // File the section was parsed from, relative to the tutorials directory
+	Duration        string        `json:"duration,omitempty"`        // e.g. "3-4 minutes"
+	Objectives      []string      `json:"objectives,omitempty"`      // What the reader should be able to do afterwards
+	Tags            []string      `json:"tags,omitempty"`
+	Prerequisites   []string      `json:"prerequisites,omitempty"` // IDs of sections to read first
+	MinGoVersion    string        `json:"minGoVersion,omitempty"`  // Oldest Go release the section's code works with, e.g. "1.22"
+	// CheatsheetLinks point the section's topics at the cheatsheet entries that cover them
+	CheatsheetLinks []CheatsheetLink `json:"cheatsheetLinks,omitempty"`
+	// Positions locate the title, duration, topics and teaching points in the source file
+	Positions *SectionPositions `json:"positions,omitempty"`
+}
+
+// SourcePosition is where an element starts in a section's source file, 1-based
+type SourcePosition struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// SectionPositions locates the elements parsed from a section's Markdown. Elements taken
+// from front matter have no position.
+type SectionPositions struct {
+	Title          *SourcePosition  `json:"title,omitempty"`
+	Duration       *SourcePosition  `json:"duration,omitempty"`
+	Topics         []SourcePosition `json:"topics,omitempty"`         // One per topic
+	TeachingPoints []SourcePosition `json:"teachingPoints,omitempty"` // One per teaching point
+}
+
+// CheatsheetLink connects a section topic to a cheatsheet entry about it
+type CheatsheetLink struct {
+	Topic        string `json:"topic"` // The topic as written in the section
+	CheatsheetID string `json:"cheatsheetId"`
+	EntryID      string `json:"entryId"`
+	Title        string `json:"title"` // Title of the entry
 }
 
 // CodeExample represents a code example within a section
 type CodeExample struct {
-	ID             string `json:"id"`
-	Code           string `json:"code"`
-	Language       string `json:"language"`
-	Runnable       bool   `json:"runnable"`
-	Snippet        bool   `json:"snippet,omitempty"` // If true, code needs wrapping before execution
-	ExpectedOutput string `json:"expectedOutput,omitempty"`
-	Description    string `json:"description,omitempty"`
+	ID              string       `json:"id"`
+	Code            string       `json:"code"`
+	Language        string       `json:"language"`
+	Runnable        bool         `json:"runnable"`
+	Snippet         bool         `json:"snippet,omitempty"`         // If true, code needs wrapping before execution
+	Toolchain       string       `json:"toolchain,omitempty"`       // Go toolchain the example is pinned to, e.g. "1.22"
+	ExpectedOutput  string       `json:"expectedOutput,omitempty"`  // From a following output fence or a trailing "// Output:" comment
+	UnorderedOutput bool         `json:"unorderedOutput,omitempty"` // Expected output lines may appear in any order
+	Description     string       `json:"description,omitempty"`
+	Fixtures        []Fixture    `json:"fixtures,omitempty"` // Input files mounted read-only when the example runs
+	Run             *RunSettings `json:"run,omitempty"`      // Resource settings for running the example
+	Line            int          `json:"line,omitempty"`     // Line of the opening fence in the section's source file
+	Column          int          `json:"column,omitempty"`   // Column of the opening fence, past any list indentation
+	EndLine         int          `json:"endLine,omitempty"`  // Line of the closing fence
+	// ExpectedFailure is set for examples that demonstrate a compiler or runtime error
+	ExpectedFailure *ExpectedFailure `json:"expectedFailure,omitempty"`
+}
+
+// ExpectedFailure is how a code example that demonstrates an error is expected to fail
+type ExpectedFailure struct {
+	Kind    string `json:"kind"`              // "compile-error", "panic" or "deadlock"
+	Message string `json:"message,omitempty"` // Text the error output must contain
+}
+
+// RunSettings are the resources a code example asks for when it runs, which the server
+// clamps to its configured maximums
+type RunSettings struct {
+	Timeout   string `json:"timeout,omitempty"`   // Duration such as "5s"
+	Memory    int    `json:"memory,omitempty"`    // Memory limit in MB
+	CPU       int    `json:"cpu,omitempty"`       // CPU limit as a percentage of one core
+	MaxOutput int    `json:"maxOutput,omitempty"` // Output limit in bytes
+}
+
+// Fixture is an input file a code example reads, e.g. data.txt
+type Fixture struct {
+	Name     string `json:"name"`
+	Content  string `json:"content"`
+	Encoding string `json:"encoding,omitempty"` // "base64" when the content is not UTF-8 text
 }
 
 // Exercise represents a practice exercise
@@ -57,3 +124,27 @@ type Exercise struct {
 	Solution    string   `json:"solution,omitempty"`
 	StarterCode string   `json:"starterCode,omitempty"`
 }
+
+// Cheatsheet is a quick reference page, split into entries at its headings
+type Cheatsheet struct {
+	ID      string            `json:"id"` // File name without the extension, e.g. "go-basics"
+	Title   string            `json:"title"`
+	Intro   string            `json:"intro,omitempty"` // Markdown before the first entry
+	Entries []CheatsheetEntry `json:"entries"`
+}
+
+// CheatsheetMetadata represents basic cheatsheet information without its entries
+type CheatsheetMetadata struct {
+	ID         string `json:"id"`
+	Title      string `json:"title"`
+	EntryCount int    `json:"entryCount"`
+}
+
+// CheatsheetEntry is the part of a cheatsheet under one heading
+type CheatsheetEntry struct {
+	ID           string        `json:"id"` // Anchor of the heading, e.g. "waitgroup"
+	Title        string        `json:"title"`
+	Group        string        `json:"group,omitempty"` // Title of the heading the entry is nested under, e.g. "sync Package"
+	Content      string        `json:"content"`         // Markdown content without the heading
+	CodeExamples []CodeExample `json:"codeExamples"`
+}